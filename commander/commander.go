@@ -0,0 +1,197 @@
+// Package commander provides the dual-pane file manager in the style of
+// Norton Commander, built of two filemgr panes.
+package commander
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/rusq/rbubbles/filemgr"
+)
+
+// Model is the dual-pane file manager.  Tab switches between the panes, the
+// files of the active pane are copied or moved to the directory of the
+// other one.
+type Model struct {
+	// Panes are the left and the right panes.  The panes share the file
+	// system, which must be the filemgr.WriteFS for file operations.
+	Panes [2]filemgr.Model
+	// Sync makes the other pane follow the active one, when it enters
+	// a subdirectory, that exists in both panes, or goes up.
+	Sync bool
+	// ShowHelp shows the commander keys beneath the panes.
+	ShowHelp bool
+	// Top is the screen row of the view, used to locate the mouse events.
+	Top   int
+	Keys  KeyMap
+	Style Style
+
+	active int // index of the active pane
+	help   help.Model
+}
+
+type Style struct {
+	Inactive lipgloss.Style // inactive pane
+	Status   lipgloss.Style
+}
+
+// New returns the commander with the panes showing the directories left
+// and right of fsys.  The panes have multi-select, file operations and
+// the path line enabled.
+func New(fsys fs.FS, left, right string, height int, globs ...string) Model {
+	m := Model{
+		Panes: [2]filemgr.Model{
+			filemgr.New(fsys, left, height, globs...),
+			filemgr.New(fsys, right, height, globs...),
+		},
+		Keys: DefaultKeyMap(),
+		help: help.New(),
+		Style: Style{
+			Inactive: lipgloss.NewStyle().Faint(true),
+			Status:   lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
+		},
+	}
+	for i := range m.Panes {
+		m.Panes[i].MultiSelect = true
+		m.Panes[i].FileOps = true
+		m.Panes[i].ShowPath = true
+	}
+	m.Panes[1].Left = filemgr.DefaultWidth
+	m.Panes[0].Focus()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.Panes[0].Init(), m.Panes[1].Init())
+}
+
+// Active returns the index of the active pane.
+func (m Model) Active() int {
+	return m.active
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg)
+	case tea.KeyMsg:
+		if m.Panes[m.active].Modal() {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.Keys.Switch):
+			m.focus(1 - m.active)
+			return m, nil
+		case key.Matches(msg, m.Keys.Copy):
+			return m, m.Panes[m.active].CopyTo(m.other().Directory)
+		case key.Matches(msg, m.Keys.Move):
+			return m, m.Panes[m.active].MoveTo(m.other().Directory)
+		case key.Matches(msg, m.Keys.Sync):
+			m.Sync = !m.Sync
+			return m, nil
+		}
+		return m.updateActive(msg)
+	case tea.MouseMsg:
+		i := 0
+		if msg.X >= m.Panes[1].Left {
+			i = 1
+		}
+		if i != m.active && msg.Action == tea.MouseActionPress && !m.Panes[m.active].Modal() {
+			m.focus(i)
+		}
+		return m.updateActive(msg)
+	case filemgr.WMFileOp:
+		// the panes may have been switched while the operation was running,
+		// so both are reloaded.
+		return m, tea.Batch(m.Panes[0].Reload(), m.Panes[1].Reload())
+	}
+	// the rest goes to both panes, they pick their own messages.
+	var cmds [2]tea.Cmd
+	for i := range m.Panes {
+		m.Panes[i], cmds[i] = m.Panes[i].Update(msg)
+	}
+	return m, tea.Batch(cmds[:]...)
+}
+
+// updateActive passes the message to the active pane, and makes the other
+// pane follow, if it changed the directory.
+func (m Model) updateActive(msg tea.Msg) (Model, tea.Cmd) {
+	from := m.Panes[m.active].Directory
+	var cmd tea.Cmd
+	m.Panes[m.active], cmd = m.Panes[m.active].Update(msg)
+	if to := m.Panes[m.active].Directory; m.Sync && to != from {
+		return m, tea.Batch(cmd, m.follow(from, to))
+	}
+	return m, cmd
+}
+
+// follow changes the directory of the other pane, if the active pane has
+// entered the subdirectory, or went up.
+func (m *Model) follow(from, to string) tea.Cmd {
+	other := m.other()
+	var dir string
+	switch {
+	case filepath.Dir(to) == from:
+		dir = filepath.Join(other.Directory, filepath.Base(to))
+	case filepath.Dir(from) == to:
+		dir = filepath.Dir(other.Directory)
+	default:
+		return nil
+	}
+	if dir == other.Directory {
+		return nil
+	}
+	if fi, err := fs.Stat(other.FS, filepath.ToSlash(dir)); err != nil || !fi.IsDir() {
+		return nil
+	}
+	return other.Chdir(dir)
+}
+
+func (m *Model) other() *filemgr.Model {
+	return &m.Panes[1-m.active]
+}
+
+func (m *Model) focus(i int) {
+	m.Panes[m.active].Blur()
+	m.active = i
+	m.Panes[m.active].Focus()
+}
+
+// resize splits the window between the panes.
+func (m *Model) resize(msg tea.WindowSizeMsg) {
+	height := msg.Height
+	if m.ShowHelp {
+		height -= 2
+	}
+	left := msg.Width / 2
+	m.Panes[0].Width, m.Panes[1].Width = left, msg.Width-left
+	m.Panes[1].Left = left
+	for i := range m.Panes {
+		m.Panes[i].Height = height
+		m.Panes[i].Top = m.Top
+	}
+}
+
+func (m Model) View() string {
+	var views [2]string
+	for i := range m.Panes {
+		views[i] = m.Panes[i].View()
+		if i != m.active {
+			views[i] = m.Style.Inactive.Render(views[i])
+		}
+	}
+	v := lipgloss.JoinHorizontal(lipgloss.Top, views[0], views[1])
+	if m.ShowHelp {
+		status := m.help.View(m.Keys)
+		if m.Sync {
+			status += m.help.ShortSeparator + "sync on"
+		}
+		v += "\n" + m.Style.Status.Render(status)
+	}
+	return v
+}
@@ -0,0 +1,134 @@
+package commander
+
+import (
+	"io/fs"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusq/rbubbles/filemgr"
+)
+
+// run feeds the messages produced by cmd back to the model.
+func run(m Model, cmd tea.Cmd) Model {
+	if cmd == nil {
+		return m
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			m = run(m, c)
+		}
+		return m
+	}
+	m, cmd = m.Update(msg)
+	return run(m, cmd)
+}
+
+func press(m Model, msg tea.KeyMsg) Model {
+	m, cmd := m.Update(msg)
+	return run(m, cmd)
+}
+
+func testModel(t *testing.T) (Model, *filemgr.MemFS) {
+	fsys := filemgr.NewMemFS()
+	require.NoError(t, fsys.Mkdir("src", 0o755))
+	require.NoError(t, fsys.Mkdir("src/sub", 0o755))
+	require.NoError(t, fsys.Mkdir("src/only", 0o755))
+	require.NoError(t, fsys.WriteFile("src/file.txt", []byte("data"), 0o644))
+	require.NoError(t, fsys.Mkdir("dst", 0o755))
+	require.NoError(t, fsys.Mkdir("dst/sub", 0o755))
+
+	m := New(fsys, "src", "dst", 10, "*")
+	return run(m, m.Init()), fsys
+}
+
+func TestModel_switch(t *testing.T) {
+	m, _ := testModel(t)
+	assert.Equal(t, 0, m.Active())
+	assert.True(t, m.Panes[0].Focused())
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 1, m.Active())
+	assert.False(t, m.Panes[0].Focused())
+	assert.True(t, m.Panes[1].Focused())
+
+	// click on the left pane activates it
+	m, _ = m.Update(tea.MouseMsg{X: 1, Y: 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 0, m.Active())
+}
+
+func TestModel_copy(t *testing.T) {
+	m, fsys := testModel(t)
+	// marks of the other pane are kept
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m.Panes[1].Select("sub")
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m.Panes[0].Select("file.txt")
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyF6})
+	assert.True(t, m.Panes[0].Modal())
+	assert.Contains(t, m.View(), "Move to: /dst")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.Panes[0].Modal())
+
+	_, err := fs.Stat(fsys, "src/file.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Contains(t, m.View(), "file.txt", "the other pane is reloaded")
+	assert.Equal(t, []string{"dst/sub"}, m.Panes[1].Marked())
+	m.Panes[1].ClearMarks()
+
+	// copy it back
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m.Panes[1].Select("file.txt")
+	m = press(m, tea.KeyMsg{Type: tea.KeyF5})
+	assert.Contains(t, m.View(), "Copy to: /src")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	for _, name := range []string{"src/file.txt", "dst/file.txt"} {
+		data, err := fs.ReadFile(fsys, name)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(data))
+	}
+}
+
+func TestModel_copySwitched(t *testing.T) {
+	m, _ := testModel(t)
+	m.Panes[0].Select("file.txt")
+	m = press(m, tea.KeyMsg{Type: tea.KeyF5})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// the user switches the panes before the copy is finished
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m = run(m, cmd)
+	assert.Contains(t, m.Panes[1].View(), "file.txt", "the destination is reloaded")
+}
+
+func TestModel_sync(t *testing.T) {
+	m, _ := testModel(t)
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.True(t, m.Sync)
+
+	m.Panes[0].Select("sub")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "src/sub", m.Panes[0].Directory)
+	assert.Equal(t, "dst/sub", m.Panes[1].Directory)
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "src", m.Panes[0].Directory)
+	assert.Equal(t, "dst", m.Panes[1].Directory)
+
+	// the other pane doesn't follow into the directory it doesn't have
+	m.Panes[0].Select("only")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "src/only", m.Panes[0].Directory)
+	assert.Equal(t, "dst", m.Panes[1].Directory)
+
+	// the right pane leads after Tab
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, ".", m.Panes[1].Directory)
+	assert.Equal(t, "src", m.Panes[0].Directory)
+}
@@ -0,0 +1,34 @@
+package commander
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap defines the key bindings of the commander.  The keys that are not
+// bound here are passed to the active pane.
+type KeyMap struct {
+	Switch key.Binding // switches to the other pane
+	Copy   key.Binding // copies files to the other pane
+	Move   key.Binding // moves files to the other pane
+	Sync   key.Binding // toggles the synchronized navigation
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Switch: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "other pane")),
+		Copy:   key.NewBinding(key.WithKeys("f5"), key.WithHelp("f5", "copy")),
+		Move:   key.NewBinding(key.WithKeys("f6"), key.WithHelp("f6", "move")),
+		Sync:   key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "sync")),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Switch, k.Copy, k.Move, k.Sync}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/rusq/rbubbles/display"
 	"github.com/rusq/rbubbles/filemgr"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,6 +28,11 @@ type Model struct {
 	st        display.State
 	err       error
 	Style     Styles
+	// Keys are the key bindings, initialised with DefaultKeyMap.
+	Keys KeyMap
+	// ShowHelp shows the key help beneath the items.
+	ShowHelp bool
+	help     help.Model
 	fields
 }
 
@@ -57,6 +64,8 @@ func NewModel(items []Item) Model {
 		Items:     items,
 		nameColSz: maxNameLen,
 		Cursor:    "",
+		Keys:      DefaultKeyMap(),
+		help:      help.New(),
 		Style: Styles{
 			Normal:      defStyle,
 			Selected:    defStyle.Copy().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("7")),
@@ -89,23 +98,24 @@ func (m Model) procMsgView(msg tea.Msg) (Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.st.SetMax(msg.Height)
+		m.filemgr, _ = m.filemgr.Update(msg)
 		m.filemgr.Height = 10
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "j", "down":
+		switch {
+		case key.Matches(msg, m.Keys.Down):
 			m.st.Down(len(m.Items))
-		case "k", "up":
+		case key.Matches(msg, m.Keys.Up):
 			m.st.Up()
-		case "home":
+		case key.Matches(msg, m.Keys.Home):
 			m.st.Home(len(m.Items))
-		case "end":
+		case key.Matches(msg, m.Keys.End):
 			m.st.End(len(m.Items), len(m.Items))
-		case " ":
+		case key.Matches(msg, m.Keys.Toggle):
 			if m.Items[m.st.Cursor].Type() != TCheckbox {
 				break
 			}
 			fallthrough
-		case "enter", "f4":
+		case key.Matches(msg, m.Keys.Edit):
 			item := m.Items[m.st.Cursor]
 
 			m.edittype = item.Type()
@@ -123,8 +133,9 @@ func (m Model) procMsgView(msg tea.Msg) (Model, tea.Cmd) {
 			case TRadio:
 				m.radio.SetValues(item.AllowedValues(), item.Value())
 				m.editing = true
-			case TFileExisting:
+			case TFile, TFileExisting:
 				m.editing = true
+				m.filemgr.SaveAs = m.edittype == TFile
 				m.filemgr.Focus()
 				cmds = append(cmds, m.filemgr.Init())
 				m.filemgr.Select(item.Value())
@@ -149,15 +160,18 @@ OUTER:
 			m.editing = false
 		}
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		if (m.edittype == TFile || m.edittype == TFileExisting) && m.filemgr.Modal() {
+			break // the file manager closes its own prompts
+		}
+		switch {
+		case key.Matches(msg, m.Keys.Save):
 			// we only process enter for non-multiline modes
 			switch m.edittype {
-			case TMultiline, TFileExisting:
+			case TMultiline, TFile, TFileExisting:
 				break OUTER
 			}
 			fallthrough
-		case "esc":
+		case key.Matches(msg, m.Keys.Close):
 			m.editing = false
 			var val string
 			switch m.edittype {
@@ -169,7 +183,7 @@ OUTER:
 				val = m.textarea.Value()
 			case TRadio:
 				val = m.radio.Value()
-			case TFileExisting:
+			case TFile, TFileExisting:
 				if m.filemgr.Selected == "" {
 					val = m.Items[m.st.Cursor].Value()
 				} else {
@@ -190,7 +204,7 @@ OUTER:
 		m.textarea, cmd = m.textarea.Update(msg)
 	case TRadio:
 		m.radio, cmd = m.radio.Update(msg)
-	case TFileExisting:
+	case TFile, TFileExisting:
 		m.filemgr, cmd = m.filemgr.Update(msg)
 	}
 	cmds = append(cmds, cmd)
@@ -205,11 +219,16 @@ func (m Model) View() string {
 	if m.err != nil {
 		return m.err.Error()
 	}
+	var v string
 	if m.editing && m.edittype != TCheckbox {
-		return m.editView()
+		v = m.editView()
 	} else {
-		return m.selectView()
+		v = m.selectView()
+	}
+	if m.ShowHelp {
+		v += "\n" + m.help.View(m.activeKeys())
 	}
+	return v
 }
 
 func (m Model) selectView() string {
@@ -233,7 +252,7 @@ func (m Model) selectView() string {
 		}
 		var val string
 		switch item.Type() {
-		case TMultiline, TText, TFileExisting:
+		case TMultiline, TText, TFile, TFileExisting:
 			val = display.Trunc(value, m.width)
 		case TCheckbox:
 			if value == sTrue {
@@ -273,7 +292,7 @@ func (m Model) editView() string {
 		v = m.textarea.View()
 	case TRadio:
 		v = m.radio.View()
-	case TFileExisting:
+	case TFile, TFileExisting:
 		v = m.filemgr.View()
 	default:
 		return "INTERNAL ERROR"
@@ -0,0 +1,37 @@
+package customise
+
+import (
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rusq/rbubbles/filemgr"
+)
+
+func TestModel_fileModal(t *testing.T) {
+	value := "a.txt"
+	item := VarWrapper{
+		ItemName:  "file",
+		ItemType:  TFile,
+		ValueFunc: func() string { return value },
+		SetFunc:   func(s string) error { value = s; return nil },
+	}
+	m := NewModel([]Item{item})
+	m.filemgr = filemgr.New(fstest.MapFS{"a.txt": &fstest.MapFile{}}, ".", 10, "*")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, m.editing)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab}) // focus "Save as"
+	assert.True(t, m.filemgr.Modal())
+
+	// esc goes to the file manager while it is modal
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.True(t, m.editing)
+	assert.False(t, m.filemgr.Modal())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.False(t, m.editing)
+	assert.Equal(t, "a.txt", value)
+}
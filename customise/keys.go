@@ -0,0 +1,66 @@
+package customise
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap defines the key bindings of the customisation screen.
+type KeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Home   key.Binding
+	End    key.Binding
+	Toggle key.Binding // toggles the checkbox
+	Edit   key.Binding // starts editing the item
+	Save   key.Binding // saves the value, except for multiline and file items
+	Close  key.Binding // saves the value and closes the editor
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:     key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("↑/k", "up")),
+		Down:   key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("↓/j", "down")),
+		Home:   key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "first")),
+		End:    key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "last")),
+		Toggle: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+		Edit:   key.NewBinding(key.WithKeys("enter", "f4"), key.WithHelp("⏎", "edit")),
+		Save:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("⏎", "save")),
+		Close:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "done")),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.Edit, k.Save, k.Close}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Home, k.End},
+		{k.Toggle, k.Edit, k.Save, k.Close},
+	}
+}
+
+// activeKeys returns the key map with the bindings, that are not available
+// for the current item, disabled.
+func (m Model) activeKeys() KeyMap {
+	k := m.Keys
+	if m.editing {
+		for _, b := range []*key.Binding{&k.Up, &k.Down, &k.Home, &k.End, &k.Toggle, &k.Edit} {
+			b.SetEnabled(false)
+		}
+		switch m.edittype {
+		case TMultiline, TFile, TFileExisting:
+			k.Save.SetEnabled(false)
+		}
+		return k
+	}
+	k.Save.SetEnabled(false)
+	k.Close.SetEnabled(false)
+	if len(m.Items) == 0 || m.Items[m.st.Cursor].Type() != TCheckbox {
+		k.Toggle.SetEnabled(false)
+	}
+	return k
+}
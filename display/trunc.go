@@ -1,17 +1,25 @@
 package display
 
-import "strings"
+import (
+	"strings"
 
+	"github.com/mattn/go-runewidth"
+)
+
+// Trunc truncates s to fit into sz terminal cells, ending it with "…".
+// Only the first line of the multiline string is kept, ending with "⏎".
 func Trunc(s string, sz int) string {
 	if sz < 1 {
 		return s
 	}
-	firstCR := strings.Index(s, "\n")
-	if -1 < firstCR && firstCR < sz {
-		return s[:firstCR] + "⏎"
-	}
-	if len(s) > sz {
-		return s[:sz-1] + "…"
+	if line, _, ok := strings.Cut(s, "\n"); ok {
+		s = line + "⏎"
 	}
-	return s
+	return runewidth.Truncate(s, sz, "…")
+}
+
+// Fit truncates s to fit into sz terminal cells, and pads it with spaces
+// to take exactly sz cells.
+func Fit(s string, sz int) string {
+	return runewidth.FillRight(Trunc(s, sz), sz)
 }
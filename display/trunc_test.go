@@ -0,0 +1,42 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrunc(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		sz   int
+		want string
+	}{
+		{"fits", "abc", 3, "abc"},
+		{"cut", "abcdef", 4, "abc…"},
+		{"no size", "abcdef", 0, "abcdef"},
+		{"runes", "├─ файл.txt", 6, "├─ фа…"},
+		{"wide", "日本語.txt", 6, "日本…"},
+		{"wide at the edge", "日本語.txt", 5, "日本…"},
+		{"multiline", "ab\ncd", 5, "ab⏎"},
+		{"multiline cut", "abcd\nef", 4, "abc…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Trunc(tt.s, tt.sz)
+			assert.Equal(t, tt.want, got)
+			if tt.sz > 0 {
+				assert.LessOrEqual(t, runewidth.StringWidth(got), tt.sz)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	assert.Equal(t, "ab   ", Fit("ab", 5))
+	assert.Equal(t, "abcd…", Fit("abcdefg", 5))
+	assert.Equal(t, "日本 ", Fit("日本", 5))
+	assert.Equal(t, "日本… ", Fit("日本語.txt", 6), "wide rune at the edge")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/rusq/rbubbles/commander"
+	"github.com/rusq/rbubbles/filemgr"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func commanderTest() {
+	cm := commander.New(filemgr.DirFS("."), ".", ".", 10, "*")
+	cm.ShowHelp = true
+	p := tea.NewProgram(cmmodel{cm}, tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		panic(err)
+	}
+}
+
+type cmmodel struct {
+	m commander.Model
+}
+
+func (c cmmodel) Init() tea.Cmd {
+	return c.m.Init()
+}
+
+func (c cmmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "q" {
+		return c, tea.Quit
+	}
+	var cmd tea.Cmd
+	c.m, cmd = c.m.Update(msg)
+	return c, cmd
+}
+
+func (c cmmodel) View() string {
+	return c.m.View()
+}
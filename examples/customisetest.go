@@ -22,6 +22,7 @@ func customiseTest() {
 		customise.RadioStringVar(&testRadio, "test choice", "This is test choice", "Test", []string{"foo", "bar"}),
 		customise.FilenameVar(&testFilename, "Filename test", "This is filename test", "Test", true),
 	})
+	c.ShowHelp = true
 	p := tea.NewProgram(custmodel{m: c})
 	_, err := p.Run()
 	if err != nil {
@@ -10,11 +10,15 @@ import (
 )
 
 func filebrowser() {
-	fm := filemgr.New(os.DirFS("."), ".", 10, "*")
+	afs := filemgr.NewArchiveFS(os.DirFS("."))
+	defer afs.Close()
+	fm := filemgr.New(afs, ".", 10, "*")
 	fm.Focus()
+	fm.ShowPath = true
+	fm.Style = fm.Style.LSColors(os.Getenv("LS_COLORS"))
 	// fm.ShowHelp = true
 	fm.Debug = os.Getenv("DEBUG") != ""
-	p := tea.NewProgram(fmmodel{fm, false})
+	p := tea.NewProgram(fmmodel{fm, false}, tea.WithMouseCellMotion())
 	r, err := p.Run()
 	if err != nil {
 		panic(err)
@@ -19,4 +19,5 @@ func main() {
 	// wiztest()
 	filebrowser()
 	// customiseTest()
+	// commanderTest()
 }
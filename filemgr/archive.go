@@ -0,0 +1,336 @@
+package filemgr
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	_ WriteFS   = (*ArchiveFS)(nil)
+	_ fs.StatFS = (*ArchiveFS)(nil)
+)
+
+// archiveMemMax is the max size of the archive contents read into memory.
+const archiveMemMax = 256 << 20
+
+// ErrArchiveTooLarge is returned when the archive, that has to be read into
+// memory, is too large.
+var ErrArchiveTooLarge = errors.New("archive is too large")
+
+// ArchiveFS is the file system, that allows browsing .zip, .tar and .tar.gz
+// files as directories: the path "export.zip/channels/general.json" refers
+// to the file inside the archive "export.zip".  Archives nested in archives
+// are supported.  Archives are read-only, the files outside of archives
+// can be modified, if the underlying file system is a WriteFS.
+//
+// Opened archives are cached, until they change.  Zip archives keep their
+// files open, the caller owns the ArchiveFS and should Close it, when it is
+// no longer used.  Tar archives are read into memory, up to 256 MiB.
+type ArchiveFS struct {
+	fsys fs.FS
+
+	mu     sync.Mutex
+	mounts map[string]*mount
+}
+
+// mount is the opened archive.
+type mount struct {
+	size    int64
+	modTime time.Time
+	fsys    fs.FS
+	closer  io.Closer // nil, if the archive is read into memory
+}
+
+// NewArchiveFS returns the ArchiveFS for the file system.
+func NewArchiveFS(fsys fs.FS) *ArchiveFS {
+	return &ArchiveFS{fsys: fsys, mounts: make(map[string]*mount)}
+}
+
+// isArchive returns true if the file is the archive, that can be entered.
+func (m Model) isArchive(fi fs.FileInfo) bool {
+	_, ok := m.FS.(*ArchiveFS)
+	return ok && fi.Mode().IsRegular() && isArchiveName(fi.Name())
+}
+
+// isArchiveName returns true if the name has an archive extension.
+func isArchiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split splits the name into the path of the outermost archive, and the path
+// inside the archive, which is "." for the archive itself.  If the name is
+// not inside an archive, archive is empty.
+func (a *ArchiveFS) Split(name string) (archive, entry string) {
+	segs := strings.Split(name, "/")
+	for i := range segs {
+		p := path.Join(segs[:i+1]...)
+		if !isArchiveName(p) {
+			continue
+		}
+		if fi, err := fs.Stat(a.fsys, p); err == nil && fi.Mode().IsRegular() {
+			return p, path.Join(append([]string{"."}, segs[i+1:]...)...)
+		}
+	}
+	return "", name
+}
+
+func (a *ArchiveFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	archive, entry := a.Split(name)
+	if archive == "" {
+		return a.fsys.Open(name)
+	}
+	if entry == "." {
+		// the archive itself is the regular file, that can be listed.
+		f, err := a.fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return &archiveFile{File: f, a: a, name: name}, nil
+	}
+	afs, err := a.mount(archive)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return afs.Open(entry)
+}
+
+func (a *ArchiveFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	archive, entry := a.Split(name)
+	if archive == "" || entry == "." {
+		return fs.Stat(a.fsys, name)
+	}
+	afs, err := a.mount(archive)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return fs.Stat(afs, entry)
+}
+
+// mount returns the file system of the archive.
+func (a *ArchiveFS) mount(name string) (fs.FS, error) {
+	fi, err := fs.Stat(a.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if mnt, ok := a.mounts[name]; ok {
+		if mnt.size == fi.Size() && mnt.modTime.Equal(fi.ModTime()) {
+			return mnt.fsys, nil
+		}
+		mnt.close()
+		delete(a.mounts, name)
+	}
+	mnt, err := openArchive(a.fsys, name, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+	mnt.size, mnt.modTime = fi.Size(), fi.ModTime()
+	a.mounts[name] = mnt
+	return mnt.fsys, nil
+}
+
+// Close releases the opened archives.
+func (a *ArchiveFS) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var errs []error
+	for name, mnt := range a.mounts {
+		errs = append(errs, mnt.close())
+		delete(a.mounts, name)
+	}
+	return errors.Join(errs...)
+}
+
+func (mnt *mount) close() error {
+	var errs []error
+	if afs, ok := mnt.fsys.(*ArchiveFS); ok {
+		errs = append(errs, afs.Close()) // nested archives
+	}
+	if mnt.closer != nil {
+		errs = append(errs, mnt.closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// openArchive opens the archive.  Zip archives are read directly from the
+// file, if it supports io.ReaderAt, otherwise archives are read into memory.
+func openArchive(fsys fs.FS, name string, size int64) (*mount, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(strings.ToLower(name), ".zip") {
+		defer f.Close()
+		tfs, err := readTar(f, !strings.HasSuffix(strings.ToLower(name), ".tar"), archiveMemMax)
+		if err != nil {
+			return nil, err
+		}
+		return &mount{fsys: NewArchiveFS(tfs)}, nil
+	}
+	mnt := &mount{closer: f}
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		if size > archiveMemMax {
+			f.Close()
+			return nil, ErrArchiveTooLarge
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		ra, mnt.closer = bytes.NewReader(data), nil
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		mnt.close()
+		return nil, err
+	}
+	mnt.fsys = NewArchiveFS(zr)
+	return mnt, nil
+}
+
+// readTar reads the tar archive, optionally gzipped, into memory.  It
+// returns ErrArchiveTooLarge, if the files take more than limit bytes.
+func readTar(r io.Reader, gzipped bool, limit int64) (fs.FS, error) {
+	if gzipped {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	tfs := mapFS{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tfs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			tfs[name] = &mapFile{Mode: fs.ModeDir | hdr.FileInfo().Mode().Perm(), ModTime: hdr.ModTime}
+		case tar.TypeReg:
+			if limit -= hdr.Size; limit < 0 {
+				return nil, ErrArchiveTooLarge
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			tfs[name] = &mapFile{Data: data, Mode: hdr.FileInfo().Mode().Perm(), ModTime: hdr.ModTime}
+		}
+	}
+}
+
+// archiveFile is the archive file, that can be read as a directory.
+type archiveFile struct {
+	fs.File
+	a    *ArchiveFS
+	name string
+	dir  fs.ReadDirFile // root of the archive, opened on the first ReadDir
+}
+
+func (f *archiveFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.dir == nil {
+		afs, err := f.a.mount(f.name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: err}
+		}
+		root, err := afs.Open(".")
+		if err != nil {
+			return nil, err
+		}
+		dir, ok := root.(fs.ReadDirFile)
+		if !ok {
+			root.Close()
+			return nil, &fs.PathError{Op: "readdir", Path: ".", Err: errors.New("not implemented")}
+		}
+		f.dir = dir
+	}
+	return f.dir.ReadDir(n)
+}
+
+func (f *archiveFile) Close() error {
+	if f.dir != nil {
+		f.dir.Close()
+	}
+	return f.File.Close()
+}
+
+// writeFS returns the underlying WriteFS, if none of the names is inside
+// an archive.
+func (a *ArchiveFS) writeFS(names ...string) (WriteFS, error) {
+	wfs, ok := a.fsys.(WriteFS)
+	if !ok {
+		return nil, ErrReadOnly
+	}
+	for _, name := range names {
+		if archive, entry := a.Split(name); archive != "" && entry != "." {
+			return nil, ErrReadOnly
+		}
+	}
+	return wfs, nil
+}
+
+func (a *ArchiveFS) Mkdir(name string, perm fs.FileMode) error {
+	wfs, err := a.writeFS(name)
+	if err != nil {
+		return err
+	}
+	return wfs.Mkdir(name, perm)
+}
+
+func (a *ArchiveFS) Rename(oldname, newname string) error {
+	wfs, err := a.writeFS(oldname, newname)
+	if err != nil {
+		return err
+	}
+	return wfs.Rename(oldname, newname)
+}
+
+func (a *ArchiveFS) Remove(name string) error {
+	wfs, err := a.writeFS(name)
+	if err != nil {
+		return err
+	}
+	return wfs.Remove(name)
+}
+
+func (a *ArchiveFS) Copy(src, dst string) error {
+	wfs, err := a.writeFS(src, dst)
+	if err != nil {
+		return err
+	}
+	return wfs.Copy(src, dst)
+}
@@ -0,0 +1,169 @@
+package filemgr
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, data := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func archiveTestFS(t *testing.T) fstest.MapFS {
+	return fstest.MapFS{
+		"export.zip": &fstest.MapFile{Data: makeZip(t, map[string]string{
+			"channels.json":        "[]",
+			"general/2024-01.json": "{}",
+		})},
+		"logs.tar.gz": &fstest.MapFile{Data: makeTarGz(t, map[string]string{
+			"app.log":          "hello",
+			"./old/inner.zip":  string(makeZip(t, map[string]string{"deep.txt": "deep"})),
+			"/absolute/x.json": "{}",
+		})},
+		"fake.zip/file.txt": &fstest.MapFile{Data: []byte("a directory named .zip")},
+	}
+}
+
+func TestArchiveFS(t *testing.T) {
+	afs := NewArchiveFS(archiveTestFS(t))
+	defer afs.Close()
+
+	names := func(dir string) []string {
+		entries, err := fs.ReadDir(afs, dir)
+		require.NoError(t, err)
+		var s []string
+		for _, de := range entries {
+			s = append(s, de.Name())
+		}
+		return s
+	}
+	assert.Equal(t, []string{"export.zip", "fake.zip", "logs.tar.gz"}, names("."))
+	assert.Equal(t, []string{"channels.json", "general"}, names("export.zip"))
+	assert.Equal(t, []string{"absolute", "app.log", "old"}, names("logs.tar.gz"))
+	assert.Equal(t, []string{"file.txt"}, names("fake.zip"))
+
+	data, err := fs.ReadFile(afs, "logs.tar.gz/old/inner.zip/deep.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "deep", string(data))
+
+	fi, err := fs.Stat(afs, "export.zip")
+	require.NoError(t, err)
+	assert.False(t, fi.IsDir())
+	fi, err = fs.Stat(afs, "export.zip/general")
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+
+	archive, entry := afs.Split("logs.tar.gz/old/inner.zip/deep.txt")
+	assert.Equal(t, "logs.tar.gz", archive)
+	assert.Equal(t, "old/inner.zip/deep.txt", entry)
+	archive, entry = afs.Split("fake.zip/file.txt")
+	assert.Equal(t, "", archive)
+	assert.Equal(t, "fake.zip/file.txt", entry)
+}
+
+func TestArchiveFS_Close(t *testing.T) {
+	afs := NewArchiveFS(archiveTestFS(t))
+	_, err := fs.Stat(afs, "logs.tar.gz/old/inner.zip/deep.txt")
+	require.NoError(t, err)
+	inner := afs.mounts["logs.tar.gz"].fsys.(*ArchiveFS)
+	assert.Len(t, inner.mounts, 1)
+
+	require.NoError(t, afs.Close())
+	assert.Empty(t, afs.mounts)
+	assert.Empty(t, inner.mounts, "nested archives are released")
+}
+
+func Test_readTar(t *testing.T) {
+	data := makeTarGz(t, map[string]string{"a.txt": "hello", "b.txt": "world"})
+	_, err := readTar(bytes.NewReader(data), true, 10)
+	assert.NoError(t, err)
+	_, err = readTar(bytes.NewReader(data), true, 9)
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+	assert.Equal(t, "Archive too large", errorText(&fs.PathError{Op: "open", Path: "x.tar", Err: err}))
+}
+
+func TestArchiveFS_write(t *testing.T) {
+	mfs := NewMemFS()
+	require.NoError(t, mfs.WriteFile("a.zip", makeZip(t, map[string]string{"x": "x"}), 0o644))
+	afs := NewArchiveFS(mfs)
+
+	assert.ErrorIs(t, afs.Mkdir("a.zip/dir", 0o755), ErrReadOnly)
+	assert.ErrorIs(t, afs.Copy("a.zip/x", "x"), ErrReadOnly)
+	assert.NoError(t, afs.Copy("a.zip", "b.zip"))
+	assert.NoError(t, afs.Remove("a.zip"))
+	_, err := fs.Stat(afs, "b.zip/x")
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, NewArchiveFS(fstest.MapFS{}).Mkdir("dir", 0o755), ErrReadOnly)
+}
+
+func TestModel_archive(t *testing.T) {
+	m := New(NewArchiveFS(archiveTestFS(t)), ".", 10, "*")
+	m.Focus()
+	m = run(m, m.load())
+	enter := func(m Model) (Model, []tea.Msg) {
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msgs := collectMsgs(cmd)
+		for _, msg := range msgs {
+			m = run(m, func() tea.Msg { return msg })
+		}
+		return m, msgs
+	}
+
+	m.Select("export.zip")
+	m, _ = enter(m)
+	assert.Equal(t, "export.zip", m.Directory)
+	assert.Equal(t, []string{"..", "general", "channels.json"}, fileNames(m.files))
+
+	m.Select("channels.json")
+	_, msgs := enter(m)
+	assert.Contains(t, msgs, WMSelected{Filepath: "export.zip/channels.json", Archive: "export.zip", Entry: "channels.json"})
+
+	// back to the root
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = run(m, m.load())
+	assert.Equal(t, ".", m.Directory)
+	assert.Equal(t, "export.zip", m.current())
+}
+
+func fileNames(files []fs.FileInfo) []string {
+	names := make([]string, len(files))
+	for i, fi := range files {
+		names[i] = fi.Name()
+	}
+	return names
+}
@@ -0,0 +1,283 @@
+package filemgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// maxRecent is the number of the recently visited directories to keep.
+const maxRecent = 10
+
+// Bookmarks are the pinned and the most recently visited directories.
+// Directories are relative to the root of the file system of the model.
+type Bookmarks struct {
+	Pinned []string `json:"pinned,omitempty"`
+	Recent []string `json:"recent,omitempty"`
+}
+
+// BookmarkStore loads and saves bookmarks.
+type BookmarkStore interface {
+	Load() (Bookmarks, error)
+	Save(Bookmarks) error
+}
+
+// JSONStore is the BookmarkStore that keeps bookmarks in a JSON file.
+type JSONStore struct {
+	// Path is the path to the file.  If empty, DefaultBookmarkPath is used.
+	Path string
+}
+
+// DefaultBookmarkPath returns the path of the bookmarks file in the user
+// config directory.
+func DefaultBookmarkPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rbubbles", "bookmarks.json"), nil
+}
+
+func (s JSONStore) path() (string, error) {
+	if s.Path != "" {
+		return s.Path, nil
+	}
+	return DefaultBookmarkPath()
+}
+
+// Load loads the bookmarks.  If the file does not exist, it returns empty
+// bookmarks.
+func (s JSONStore) Load() (Bookmarks, error) {
+	var b Bookmarks
+	p, err := s.path()
+	if err != nil {
+		return b, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return b, nil
+		}
+		return b, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&b); err != nil && err != io.EOF {
+		return b, fmt.Errorf("%s: %w", p, err)
+	}
+	return b, nil
+}
+
+// Save saves the bookmarks, creating the directory, if necessary.
+func (s JSONStore) Save(b Bookmarks) error {
+	p, err := s.path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o600)
+}
+
+// wmBookmarks is sent when the bookmarks are loaded from the store.
+type wmBookmarks struct {
+	model uint64
+	b     Bookmarks
+	err   error
+}
+
+// wmBookmarksSaved is sent when the bookmarks are saved to the store.
+type wmBookmarksSaved struct {
+	err error
+}
+
+func (m Model) loadBookmarksCmd() tea.Cmd {
+	if m.Store == nil {
+		return nil
+	}
+	model, store := m.id, m.Store
+	return func() tea.Msg {
+		b, err := store.Load()
+		return wmBookmarks{model: model, b: b, err: err}
+	}
+}
+
+// saveBookmarksCmd returns the command that saves the bookmarks.  Nothing
+// is saved until the bookmarks are loaded, so that the stored ones are not
+// overwritten.
+func (m Model) saveBookmarksCmd() tea.Cmd {
+	if m.Store == nil || !m.bookmarksLoaded {
+		return nil
+	}
+	store, b := m.Store, Bookmarks{
+		Pinned: slices.Clone(m.bookmarks.Pinned),
+		Recent: slices.Clone(m.bookmarks.Recent),
+	}
+	return func() tea.Msg {
+		return wmBookmarksSaved{err: store.Save(b)}
+	}
+}
+
+// setBookmarks merges the loaded bookmarks with the directories visited
+// before they were loaded.
+func (m *Model) setBookmarks(b Bookmarks) tea.Cmd {
+	visited := m.bookmarks.Recent
+	m.bookmarks = b
+	m.bookmarksLoaded = true
+	for i := len(visited) - 1; i >= 0; i-- {
+		m.visit(visited[i])
+	}
+	return m.saveBookmarksCmd()
+}
+
+// visit puts the directory on top of the recent list.
+func (m *Model) visit(dir string) {
+	dir = fsDir(dir)
+	recent := slices.DeleteFunc(m.bookmarks.Recent, func(s string) bool { return s == dir })
+	recent = slices.Insert(recent, 0, dir)
+	m.bookmarks.Recent = recent[:min(len(recent), maxRecent)]
+}
+
+// togglePin pins the current directory, or unpins it, if it is pinned.
+func (m *Model) togglePin() tea.Cmd {
+	dir := fsDir(m.Directory)
+	if i := slices.Index(m.bookmarks.Pinned, dir); i >= 0 {
+		m.bookmarks.Pinned = slices.Delete(m.bookmarks.Pinned, i, i+1)
+	} else {
+		m.bookmarks.Pinned = append(m.bookmarks.Pinned, dir)
+	}
+	return m.saveBookmarksCmd()
+}
+
+// Bookmarks returns the pinned and the recent directories.
+func (m Model) Bookmarks() Bookmarks {
+	return Bookmarks{
+		Pinned: slices.Clone(m.bookmarks.Pinned),
+		Recent: slices.Clone(m.bookmarks.Recent),
+	}
+}
+
+// Chdir changes the current directory to dir, that is relative to the root
+// of the file system.  The view stack is rebuilt, so that Backspace walks up
+// the path.
+func (m *Model) Chdir(dir string) tea.Cmd {
+	dir = fsDir(dir)
+	var stack display.Stack[display.State]
+	if dir != "." {
+		for range strings.Count(dir, "/") + 1 {
+			stack.Push(display.State{})
+		}
+	}
+	m.viewStack = stack
+	m.Directory = filepath.FromSlash(dir)
+	m.st = display.State{}
+	m.filter = ""
+	m.reveal = ""
+	return m.loadDir()
+}
+
+// placesList returns the entries of the bookmarks popup: pinned
+// directories first, then the recent ones.
+func (m Model) placesList() []string {
+	return append(slices.Clone(m.bookmarks.Pinned), m.bookmarks.Recent...)
+}
+
+// jumpPinned changes directory to the n-th pinned bookmark, starting from 1.
+func (m *Model) jumpPinned(n int) tea.Cmd {
+	if n < 1 || len(m.bookmarks.Pinned) < n {
+		return nil
+	}
+	m.places = false
+	return m.Chdir(m.bookmarks.Pinned[n-1])
+}
+
+// updatePlaces processes the key message while the bookmarks popup is
+// shown.
+func (m Model) updatePlaces(msg tea.KeyMsg) (Model, tea.Cmd) {
+	list := m.placesList()
+	switch {
+	case key.Matches(msg, m.Keys.Up):
+		m.placesSt.Up()
+	case key.Matches(msg, m.Keys.Down):
+		m.placesSt.Down(len(list))
+	case key.Matches(msg, m.Keys.Select):
+		if m.placesSt.Cursor < len(list) {
+			m.places = false
+			return m, m.Chdir(list[m.placesSt.Cursor])
+		}
+	case key.Matches(msg, m.Keys.Cancel, m.Keys.Places):
+		m.places = false
+	case key.Matches(msg, m.Keys.Pinned):
+		return m, m.jumpPinned(m.Keys.pinnedIndex(msg))
+	}
+	return m, nil
+}
+
+// updateBookmarks processes the bookmark keys.  It returns false if the key
+// is not a bookmark key.
+func (m *Model) updateBookmarks(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.Store == nil {
+		return nil, false
+	}
+	switch {
+	case key.Matches(msg, m.Keys.Pin):
+		return m.togglePin(), true
+	case key.Matches(msg, m.Keys.Places):
+		m.places = true
+		m.placesSt = display.State{}
+		m.placesSt.SetMax(m.height())
+	case key.Matches(msg, m.Keys.Pinned):
+		return m.jumpPinned(m.Keys.pinnedIndex(msg)), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// printPlaces prints the bookmarks popup in place of the listing.
+func (m Model) printPlaces(w io.Writer) {
+	var (
+		list   = m.placesList()
+		pinned = len(m.bookmarks.Pinned)
+		lines  int
+	)
+	if len(list) == 0 {
+		fmt.Fprintln(w, m.Style.Normal.Render("No bookmarks, press ["+m.Keys.Pin.Help().Key+"] to pin the directory"))
+		lines++
+	}
+	for i, dir := range list {
+		if i < m.placesSt.Min || i > m.placesSt.Max {
+			continue
+		}
+		label := "   "
+		if i < pinned && i < 9 {
+			label = fmt.Sprintf("%d. ", i+1)
+		} else if i >= pinned {
+			label = " ~ "
+		}
+		style := m.Style.Directory
+		if i == m.placesSt.Cursor {
+			style = m.Style.Inverted
+		}
+		fmt.Fprintln(w, style.Render(display.Fit(label+dir, m.width()-1)))
+		lines++
+	}
+	for ; lines < m.height(); lines++ {
+		fmt.Fprintln(w, m.Style.Normal.Render(strings.Repeat(" ", m.width()-1)))
+	}
+}
@@ -0,0 +1,82 @@
+package filemgr
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	b Bookmarks
+}
+
+func (s *memStore) Load() (Bookmarks, error) { return s.b, nil }
+func (s *memStore) Save(b Bookmarks) error   { s.b = b; return nil }
+
+func TestJSONStore(t *testing.T) {
+	s := JSONStore{Path: filepath.Join(t.TempDir(), "sub", "bookmarks.json")}
+	b, err := s.Load()
+	require.NoError(t, err, "missing file is not an error")
+	assert.Equal(t, Bookmarks{}, b)
+
+	want := Bookmarks{Pinned: []string{"dir1"}, Recent: []string{"dir2", "."}}
+	require.NoError(t, s.Save(want))
+	b, err = s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, want, b)
+}
+
+func TestModel_visit(t *testing.T) {
+	var m Model
+	for i := range maxRecent + 2 {
+		m.visit(fmt.Sprintf("dir%d", i))
+	}
+	m.visit("dir5")
+	r := m.Bookmarks().Recent
+	assert.Len(t, r, maxRecent)
+	assert.Equal(t, []string{"dir5", "dir11", "dir10"}, r[:3])
+}
+
+func TestModel_bookmarks(t *testing.T) {
+	store := &memStore{b: Bookmarks{Pinned: []string{"dir2"}}}
+	m := New(testfs, ".", 10, "*")
+	m.Store = store
+	m.Focus()
+	m = run(m, m.Init())
+	assert.Equal(t, Bookmarks{Pinned: []string{"dir2"}, Recent: []string{"."}}, store.b)
+
+	// jump to the first pinned bookmark
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}} })
+	assert.Equal(t, "dir2", m.Directory)
+	assert.Equal(t, 1, m.viewStack.Len())
+	assert.Equal(t, []string{"dir2", "."}, store.b.Recent)
+
+	// unpin, and go back
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}} })
+	assert.Empty(t, store.b.Pinned)
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyBackspace} })
+	assert.Equal(t, ".", m.Directory)
+
+	// popup shows recent directories
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\''}} })
+	assert.True(t, m.places)
+	assert.Contains(t, m.View(), " ~ dir2")
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyDown} })
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyEnter} })
+	assert.False(t, m.places)
+	assert.Equal(t, "dir2", m.Directory)
+}
+
+func TestModel_bookmarksDisabled(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Focus()
+	m = run(m, m.Init())
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\''}} })
+	assert.False(t, m.places, "no store, no bookmarks")
+	m.help.ShowAll = true
+	assert.NotContains(t, m.helpView(), "bookmarks")
+}
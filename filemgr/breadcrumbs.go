@@ -0,0 +1,140 @@
+package filemgr
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+const (
+	crumbSep  = " › "
+	crumbRoot = "/"
+	crumbCut  = "…"
+)
+
+// crumbs returns the path segments of the directory, starting with the root.
+func crumbs(dir string) []string {
+	dir = fsDir(dir)
+	if dir == "." {
+		return []string{crumbRoot}
+	}
+	return append([]string{crumbRoot}, strings.Split(dir, "/")...)
+}
+
+// crumbPath returns the directory of the n-th segment.
+func crumbPath(segs []string, n int) string {
+	if n <= 0 {
+		return "."
+	}
+	return strings.Join(segs[1:n+1], "/")
+}
+
+// fitCrumbs returns the indexes of the segments that fit into width, with -1
+// for the cut.  The root and the last segments are kept, the middle ones are
+// cut.
+func fitCrumbs(segs []string, width int) []int {
+	idx := make([]int, len(segs))
+	for i := range segs {
+		idx[i] = i
+	}
+	if len(segs) < 3 || crumbsWidth(segs, idx) <= width {
+		return idx
+	}
+	for keep := len(segs) - 2; ; keep-- {
+		idx = append(idx[:0], 0, -1)
+		for i := len(segs) - keep; i < len(segs); i++ {
+			idx = append(idx, i)
+		}
+		if keep == 1 || crumbsWidth(segs, idx) <= width {
+			return idx
+		}
+	}
+}
+
+func crumbsWidth(segs []string, idx []int) int {
+	w := lipgloss.Width(crumbSep) * (len(idx) - 1)
+	for _, i := range idx {
+		if i < 0 {
+			w += lipgloss.Width(crumbCut)
+		} else {
+			w += lipgloss.Width(segs[i])
+		}
+	}
+	return w
+}
+
+// printBreadcrumbs prints the path of the current directory.  If the crumbs
+// mode is on, the selected segment is highlighted.
+func (m Model) printBreadcrumbs(w io.Writer) {
+	segs := crumbs(m.Directory)
+	idx := fitCrumbs(segs, m.width()-1)
+	parts := make([]string, 0, len(idx))
+	for _, i := range idx {
+		if i < 0 {
+			parts = append(parts, m.Style.Normal.Render(crumbCut))
+			continue
+		}
+		style := m.Style.Breadcrumb
+		if m.crumbs && i == m.crumb {
+			style = m.Style.Inverted
+		}
+		parts = append(parts, style.Render(segs[i]))
+	}
+	line := strings.Join(parts, m.Style.Normal.Render(crumbSep))
+	if lipgloss.Width(line) > m.width()-1 {
+		// the last segment alone doesn't fit.
+		line = m.Style.Breadcrumb.Render(display.Trunc(segs[len(segs)-1], m.width()-1))
+	}
+	io.WriteString(w, line+"\n")
+}
+
+// updateCrumbs processes the key message in the crumbs mode, where the user
+// picks the ancestor directory to jump to.
+func (m Model) updateCrumbs(msg tea.KeyMsg) (Model, tea.Cmd) {
+	segs := crumbs(m.Directory)
+	switch {
+	case key.Matches(msg, m.Keys.Left):
+		m.crumb = max(0, m.crumb-1)
+	case key.Matches(msg, m.Keys.Right):
+		m.crumb = min(len(segs)-1, m.crumb+1)
+	case key.Matches(msg, m.Keys.Home):
+		m.crumb = 0
+	case key.Matches(msg, m.Keys.End):
+		m.crumb = len(segs) - 1
+	case key.Matches(msg, m.Keys.Select):
+		m.crumbs = false
+		return m, m.jumpUp(len(segs) - 1 - m.crumb)
+	case key.Matches(msg, m.Keys.Cancel, m.Keys.Path):
+		m.crumbs = false
+	}
+	return m, nil
+}
+
+// jumpUp goes n levels up from the current directory.  If the view stack
+// matches the path, the cursor positions in the ancestors are restored.
+func (m *Model) jumpUp(n int) tea.Cmd {
+	if n <= 0 {
+		return nil
+	}
+	segs := crumbs(m.Directory)
+	depth := len(segs) - 1
+	if n > depth {
+		return nil
+	}
+	if m.viewStack.Len() != depth {
+		return m.Chdir(crumbPath(segs, depth-n))
+	}
+	for range n - 1 {
+		m.viewStack.Pop()
+	}
+	m.st = m.viewStack.Pop()
+	m.Directory = filepath.FromSlash(crumbPath(segs, depth-n))
+	m.filter = ""
+	return m.loadDir()
+}
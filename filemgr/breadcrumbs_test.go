@@ -0,0 +1,80 @@
+package filemgr
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fitCrumbs(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		width int
+		want  []int
+	}{
+		{"root", ".", 10, []int{0}},
+		{"fits", "a/b/c", 20, []int{0, 1, 2, 3}},
+		{"cut one", "aaaa/bbbb/cccc", 20, []int{0, -1, 2, 3}},
+		{"cut all but last", "aaaa/bbbb/cccc", 10, []int{0, -1, 3}},
+		{"single segment too long", "aaaaaaaaaaaaaaaaaaaaa", 10, []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fitCrumbs(crumbs(tt.dir), tt.width))
+		})
+	}
+}
+
+func TestModel_printBreadcrumbs(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{".", "/\n"},
+		{"dir2", "/ › dir2\n"},
+		{"slack/exports/2024/january/channel_export_with_long_name", "/ › … › channel_export_with_long_name\n"},
+		{strings.Repeat("x", 50), strings.Repeat("x", DefaultWidth-2) + "…\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			var buf strings.Builder
+			Model{Directory: tt.dir}.printBreadcrumbs(&buf)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestModel_jumpUp(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Focus()
+	m = run(m, m.Init())
+
+	// the path is hidden, there is nothing to pick from
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	assert.False(t, m.Modal())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "dir2", m.current())
+
+	m.ShowPath = true
+	m.Select("dir2")
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyEnter} })
+	assert.Equal(t, "dir2", m.Directory)
+
+	// pick the root segment and jump there, cursor is restored
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyEnter} })
+	assert.Equal(t, ".", m.Directory)
+	assert.Equal(t, 0, m.viewStack.Len())
+	assert.Equal(t, "dir2", m.current())
+
+	// without the matching view stack, the stack is rebuilt
+	m = New(testfs, "dir2", 10, "*")
+	m.Focus()
+	assert.NotNil(t, m.jumpUp(1))
+	assert.Equal(t, ".", m.Directory)
+	assert.Equal(t, 0, m.viewStack.Len())
+}
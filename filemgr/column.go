@@ -0,0 +1,113 @@
+package filemgr
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// Column is the column of the listing, that follows the file name.
+type Column interface {
+	// Header returns the title of the column.
+	Header() string
+	// Width returns the width policy of the column.
+	Width() ColumnWidth
+	// Render returns the value of the column for the file.  The value must
+	// fit into width, it is padded to the width with spaces.
+	Render(fi fs.FileInfo, width int) string
+}
+
+// ColumnWidth is the width policy of the column.
+type ColumnWidth struct {
+	// Min is the width of the column.
+	Min int
+	// Max is the width the column is widened to, if there is room.
+	Max int
+	// Priority defines the order in which the columns are hidden, when the
+	// listing is narrow: the lowest priority column is hidden first.
+	Priority int
+	// Optional columns are shown only if there is plenty of room.
+	Optional bool
+}
+
+// NewColumn returns the column with the header, width policy and render
+// function.
+func NewColumn(header string, width ColumnWidth, render func(fi fs.FileInfo, width int) string) Column {
+	return column{header: header, width: width, render: render}
+}
+
+type column struct {
+	header string
+	width  ColumnWidth
+	render func(fi fs.FileInfo, width int) string
+}
+
+func (c column) Header() string                          { return c.header }
+func (c column) Width() ColumnWidth                      { return c.width }
+func (c column) Render(fi fs.FileInfo, width int) string { return c.render(fi, width) }
+
+// filename.extension  <DIR>  02-01-2006 15:04
+const (
+	dttmLayout     = "02-01-2006 15:04"
+	fullDttmLayout = "2006-01-02 15:04:05"
+	dirMarker      = "<DIR>"
+	filesizeSz     = 6
+	permsSz        = 10
+	ownerSz        = 8
+)
+
+// The default columns.
+var (
+	// SizeColumn shows the file size, or <DIR> for directories.
+	SizeColumn = NewColumn("Size", ColumnWidth{Min: filesizeSz, Priority: 2}, func(fi fs.FileInfo, width int) string {
+		sz := dirMarker
+		if !fi.IsDir() {
+			sz = humanizeSize(fi.Size())
+		}
+		return fmt.Sprintf("%*s", width, sz)
+	})
+	// TimeColumn shows the modification time.
+	TimeColumn = NewColumn("Modified", ColumnWidth{Min: len(dttmLayout), Priority: 1}, modTime)
+	// FullTimeColumn shows the modification time with seconds, if there
+	// is room.
+	FullTimeColumn = NewColumn("Modified", ColumnWidth{Min: len(dttmLayout), Max: len(fullDttmLayout), Priority: 1}, modTime)
+	// PermsColumn shows the file mode and permissions.
+	PermsColumn = NewColumn("Mode", ColumnWidth{Min: permsSz, Optional: true}, func(fi fs.FileInfo, _ int) string {
+		return fi.Mode().String()
+	})
+	// OwnerColumn shows the file owner, if the file system reports it.
+	OwnerColumn = NewColumn("Owner", ColumnWidth{Min: ownerSz, Optional: true}, func(fi fs.FileInfo, _ int) string {
+		return owner(fi)
+	})
+)
+
+func modTime(fi fs.FileInfo, width int) string {
+	if width >= len(fullDttmLayout) {
+		return fi.ModTime().Format(fullDttmLayout)
+	}
+	return fi.ModTime().Format(dttmLayout)
+}
+
+// DefaultColumns returns the default column set: size and modification
+// time.
+func DefaultColumns() []Column {
+	return []Column{SizeColumn, TimeColumn}
+}
+
+// columns returns the columns of the listing.  Unless set by the caller,
+// the default columns are amended according to the Show* flags.
+func (m Model) columns() []Column {
+	if m.Columns != nil {
+		return m.Columns
+	}
+	cols := []Column{SizeColumn}
+	if m.ShowPerms {
+		cols = append(cols, PermsColumn)
+	}
+	if m.ShowOwner {
+		cols = append(cols, OwnerColumn)
+	}
+	if m.ShowFullTime {
+		return append(cols, FullTimeColumn)
+	}
+	return append(cols, TimeColumn)
+}
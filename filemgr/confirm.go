@@ -0,0 +1,38 @@
+package filemgr
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmation is the yes/no question shown beneath the listing.
+type confirmation struct {
+	question string
+	yes      tea.Cmd // command to run if the user confirms
+}
+
+func (c confirmation) active() bool {
+	return c.question != ""
+}
+
+// ask shows the question, and runs the yes command, if the user confirms.
+func (m *Model) ask(question string, yes tea.Cmd) {
+	m.confirm = confirmation{question: question, yes: yes}
+}
+
+// updateConfirm processes the key message while the question is shown.
+func (m Model) updateConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.Keys.Yes):
+		cmd := m.confirm.yes
+		m.confirm = confirmation{}
+		return m, cmd
+	case key.Matches(msg, m.Keys.No):
+		m.confirm = confirmation{}
+	}
+	return m, nil
+}
+
+func (m Model) confirmView() string {
+	return m.Style.Normal.Render(m.confirm.question + " [y/N]")
+}
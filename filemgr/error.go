@@ -0,0 +1,74 @@
+package filemgr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// WMError message is sent by the file manager when the directory can't be
+// read.  The error is also shown in place of the listing, until the
+// directory is reloaded or left.
+type WMError struct {
+	Dir string
+	Err error
+}
+
+// Err returns the error of reading the current directory, or nil.
+func (m Model) Err() error {
+	return m.err
+}
+
+// loadFailed puts the model into the error state, and returns the command
+// that reports the error to the parent.
+func (m *Model) loadFailed(msg wmLoadError) tea.Cmd {
+	if msg.dir != m.Directory || msg.id < m.loadID {
+		return nil // stale
+	}
+	m.loadID = msg.id
+	m.err = msg.err
+	m.loading = false
+	m.listed = ""
+	m.populate(nil) // entries of the previous directory
+	wm := WMError{Dir: msg.dir, Err: msg.err}
+	return func() tea.Msg {
+		return wm
+	}
+}
+
+// errorText returns the short description of the error.
+func errorText(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "Permission denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "Not found"
+	case errors.Is(err, ErrArchiveTooLarge):
+		return "Archive too large"
+	}
+	return "Error"
+}
+
+// printError prints the error in place of the listing, and the keys to
+// retry or go back.
+func (m Model) printError(w io.Writer) {
+	width := m.listWidth() - 1
+	lines := []string{
+		m.Style.Error.Render(display.Fit(errorText(m.err)+": "+fsDir(m.Directory), width)),
+		m.Style.Normal.Render(display.Fit(m.err.Error(), width)),
+		m.help.ShortHelpView([]key.Binding{m.Keys.Reload, m.Keys.Back}),
+	}
+	for i := range lines[:min(len(lines), m.height())] {
+		fmt.Fprintln(w, lines[i])
+	}
+	for i := len(lines); i < m.height(); i++ {
+		fmt.Fprintln(w, m.Style.Normal.Render(strings.Repeat(" ", width)))
+	}
+}
@@ -0,0 +1,78 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deniedFS denies opening the directory.
+type deniedFS struct {
+	fs.FS
+	denied string
+}
+
+func (d deniedFS) Open(name string) (fs.File, error) {
+	if name == d.denied {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return d.FS.Open(name)
+}
+
+func TestModel_loadError(t *testing.T) {
+	m := New(deniedFS{FS: testfs, denied: "dir2"}, ".", 10, "*")
+	m.Width = 60
+	m.Focus()
+	m = run(m, m.load())
+
+	m.Select("dir2")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msgs := collectMsgs(cmd)
+	require.Len(t, msgs, 1)
+	m, cmd = m.Update(msgs[0])
+
+	// the parent is notified
+	msgs = collectMsgs(cmd)
+	require.Len(t, msgs, 1)
+	wm, ok := msgs[0].(WMError)
+	require.True(t, ok)
+	assert.Equal(t, "dir2", wm.Dir)
+	assert.ErrorIs(t, wm.Err, fs.ErrPermission)
+
+	// the error is shown instead of the listing of the parent directory
+	assert.ErrorIs(t, m.Err(), fs.ErrPermission)
+	assert.Empty(t, m.files)
+	view := m.View()
+	assert.Contains(t, view, "Permission denied: dir2")
+	assert.Contains(t, view, "ctrl+r reload • ⌫ back")
+	assert.NotContains(t, view, "No files found")
+
+	// back
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyBackspace} })
+	assert.NoError(t, m.Err())
+	assert.Equal(t, ".", m.Directory)
+	assert.Contains(t, fileNames(m.files), "dir2")
+}
+
+func TestModel_loadErrorInitial(t *testing.T) {
+	m := New(testfs, "missing/sub", 10, "*")
+	m.Focus()
+	m = run(m, m.load())
+	assert.ErrorIs(t, m.Err(), fs.ErrNotExist)
+	assert.Contains(t, m.View(), "Not found: missing/sub")
+
+	// retry fails again
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyCtrlR} })
+	assert.ErrorIs(t, m.Err(), fs.ErrNotExist)
+
+	// back goes to the parent directory, even if it was not visited
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyBackspace} })
+	assert.Equal(t, "missing", m.Directory)
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyBackspace} })
+	assert.Equal(t, ".", m.Directory)
+	assert.NoError(t, m.Err())
+	assert.Len(t, m.files, 7)
+}
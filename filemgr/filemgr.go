@@ -9,6 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -16,158 +19,286 @@ import (
 )
 
 type Model struct {
-	Globs     []string
-	Selected  string
-	FS        fs.FS
-	Directory string
-	Height    int
-	ShowHelp  bool
-	Style     Style
-	files     []fs.FileInfo
-	finished  bool
-	focus     bool
-	st        display.State
-	viewStack display.Stack[display.State]
+	// Globs are the patterns of the files to show.  Patterns prefixed with
+	// "!" hide the matching files, patterns containing "/" are matched
+	// against the path relative to the FS root, "**" matches any number of
+	// directories.  Directories are always shown.  If there are no Globs,
+	// Regexp or MatchFunc, only the directories are shown; with only the
+	// "!" patterns, Regexp or MatchFunc, the files are filtered by them.
+	Globs []string
+	// IgnoreCase makes Globs and Regexp case-insensitive.
+	IgnoreCase bool
+	// Regexp, if not empty, is the regular expression the file name must
+	// match.
+	Regexp string
+	// MatchFunc, if set, must return true for the file or directory to be
+	// shown.  name is the path relative to the FS root.
+	MatchFunc func(name string, de fs.DirEntry) bool
+	// Filters are the named filters, the user can switch between.  If set,
+	// the patterns of the active filter are used instead of Globs.
+	Filters      []NamedFilter
+	ActiveFilter int
+	Selected     string
+	FS           fs.FS
+	Directory    string
+	Height       int
+	ShowHelp     bool
+	Style        Style
+	// Width is the width of the view.  If zero, the width of the window is
+	// used.
+	Width int
+	// ShowPerms, ShowOwner and ShowFullTime add the permissions and owner
+	// columns, and the time with seconds to the default columns, if the
+	// width allows.
+	ShowPerms    bool
+	ShowOwner    bool
+	ShowFullTime bool
+	// Columns are the columns that follow the file name.  If nil, the
+	// default columns are shown.
+	Columns []Column
+	// ShowHeader shows the column titles above the listing.
+	ShowHeader bool
+	// Keys are the key bindings, initialised with DefaultKeyMap.
+	Keys KeyMap
+	// Sort is the sort key of the listing, SortDesc reverses the order, and
+	// DirsFirst places directories before files.
+	Sort      SortKey
+	SortDesc  bool
+	DirsFirst bool
+	// ShowHidden shows the dotfiles.
+	ShowHidden bool
+	// IgnoreFiles are the names of .gitignore-style files, that are read
+	// from the current directory and its parents.  Files matching the rules
+	// from these files are not shown, unless ShowHidden is set.
+	IgnoreFiles []string
+	// Preview is the position of the preview pane, PreviewWidth and
+	// PreviewHeight set its size for the right and the bottom positions
+	// respectively.
+	Preview       PreviewPosition
+	PreviewWidth  int
+	PreviewHeight int
+	// PickDir switches the model to selecting directories.  Only directories
+	// are shown, and the current directory is selected with the "." entry
+	// or ctrl+d.
+	PickDir bool
+	// SaveAs switches the model to selecting a file that may not exist.  The
+	// filename is entered beneath the listing, tab switches between the
+	// listing and the filename.
+	SaveAs bool
+	// FileOps enables the keys for file operations: F5 copy, F6 rename or
+	// move, F7 make directory, F8 delete.  FS must implement WriteFS.
+	FileOps bool
+	// Watch is the interval of polling the current directory for changes.
+	// When the directory changes, the listing is refreshed.  Zero disables
+	// watching.
+	Watch time.Duration
+	// Store keeps the bookmarks between sessions.  Pinned directories are
+	// opened with keys 1-9 or from the list shown with "'".  Bookmarks are
+	// disabled, if nil.  The paths are relative to FS, so the applications
+	// should give JSONStore a path of their own.
+	Store BookmarkStore
+	// ShowPath shows the path of the current directory above the listing.
+	// Press ctrl+u to pick the ancestor directory to jump to.
+	ShowPath bool
+	// Left and Top are the screen coordinates of the model view, used to
+	// locate the mouse events.  Mouse must be enabled in the program.
+	Left, Top int
+	// MultiSelect enables marking several files and selecting them at once.
+	MultiSelect bool
+	// Tree shows the listing as a tree.  Right expands the directory under
+	// cursor in place, Left collapses it, or moves to the parent directory.
+	Tree            bool
+	expanded        map[string][]fs.FileInfo // children of the expanded directories by path
+	entries         []fs.FileInfo            // files as read from the directory
+	ignore          ignoreRules
+	files           []fs.FileInfo
+	all             []fs.FileInfo // unfiltered listing
+	filter          string
+	highlights      map[string][]int    // matched characters by file name
+	marked          map[string]struct{} // marked file paths
+	pv              preview
+	filename        textinput.Model
+	prefilled       string // last file name put into the filename input
+	confirm         confirmation
+	id              uint64 // instance id
+	loadID          uint64 // id of the current directory load
+	loading         bool
+	err             error  // error reading the current directory
+	opErr           error  // error of the last file operation
+	listed          string // directory of the entries
+	keep            string // file to keep the cursor on while loading
+	reveal          string // file to place the cursor on, when loaded
+	completions     []string
+	gotoErr         error // error of the last "go to"
+	bookmarks       Bookmarks
+	bookmarksLoaded bool
+	crumbs          bool // picking the ancestor directory in the path
+	crumb           int  // selected path segment
+	places          bool // bookmarks popup is shown
+	placesSt        display.State
+	lastClick       click
+	winWidth        int // window width
+	help            help.Model
+	watchGen        uint64 // current watcher generation
+	prompt          promptKind
+	input           textinput.Model
+	finished        bool
+	focus           bool
+	st              display.State
+	viewStack       display.Stack[display.State]
 
 	Debug bool
 	last  string // last key pressed
 }
 
-type Style struct {
-	Normal    lipgloss.Style
-	Directory lipgloss.Style
-	Inverted  lipgloss.Style
-}
-
 // Messages
 type (
 	// WMSelected message is sent by the file manager when a file is selected.
 	WMSelected struct {
 		Filepath string
 		IsDir    bool
+		Filter   string // name of the active named filter
+		// Archive is the path of the archive, and Entry is the path inside
+		// the archive, if the file is inside one.  See ArchiveFS.
+		Archive string
+		Entry   string
 	}
 
-	wmReadDir struct {
-		dir   string
-		files []fs.FileInfo
+	// WMMultiSelected message is sent by the file manager in multi-select
+	// mode when the selection is confirmed.  Filepaths are sorted.
+	WMMultiSelected struct {
+		Filepaths []string
+		Filter    string // name of the active named filter
 	}
 )
 
 func New(fsys fs.FS, dir string, height int, globs ...string) Model {
 	return Model{
-		Globs:     globs,
-		FS:        fsys,
-		Directory: dir,
-		Height:    height,
-		focus:     false,
-		Style: Style{
-			Normal:    lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
-			Directory: lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
-			Inverted:  lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Background(lipgloss.Color("240")),
-		},
+		id:          lastModelID.Add(1),
+		Globs:       globs,
+		FS:          fsys,
+		Directory:   dir,
+		Height:      height,
+		focus:       false,
+		DirsFirst:   true,
+		IgnoreFiles: DefaultIgnoreFiles,
+		input:       textinput.New(),
+		Keys:        DefaultKeyMap(),
+		help:        help.New(),
+		filename:    newFilenameInput(),
+		Style:       DefaultStyle(),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return func() tea.Msg {
-		slog.Debug("init", "dir", m.Directory, "globs", m.Globs)
-		msg, err := readFS(m.FS, m.Directory, m.Globs...)
-		if err != nil {
-			slog.Error("readFS", "err", err)
-			return err
-		}
-		slog.Debug("readFS", "msg", msg)
-		return msg
+	slog.Debug("init", "dir", m.Directory, "globs", m.Globs)
+	if m.Store == nil {
+		return m.load()
 	}
+	return tea.Batch(m.load(), m.loadBookmarksCmd())
 }
 
-func readFS(fsys fs.FS, dir string, globs ...string) (wmReadDir, error) {
-	sub, err := fs.Sub(fsys, dir)
-	if err != nil {
-		return wmReadDir{}, err
+func (m Model) height() int {
+	h := m.Height
+	if m.ShowHelp {
+		h -= 1 + lipgloss.Height(m.helpView())
 	}
-	dirs, err := collectDirs(sub)
-	if err != nil {
-		return wmReadDir{}, err
+	if m.SaveAs {
+		h--
 	}
-	files, err := collectFiles(sub, globs...)
-	if err != nil {
-		return wmReadDir{}, err
+	if len(m.Filters) > 0 {
+		h--
 	}
-	if !(dir == "." || dir == "/" || dir == "") {
-		files = append([]fs.FileInfo{specialDir{".."}}, files...)
+	if m.ShowPath {
+		h--
 	}
-	return wmReadDir{dir, append(files, dirs...)}, nil
-}
-
-func collectFiles(fsys fs.FS, globs ...string) (files []fs.FileInfo, err error) {
-	err = fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if path == "." {
-			// do not show the current directory
-			return nil
-		}
-		if d.IsDir() {
-			return fs.SkipDir
-		}
-		for _, glob := range globs {
-			if ok, err := filepath.Match(glob, d.Name()); err != nil {
-				return err
-			} else if ok {
-				fi, err := d.Info()
-				if err != nil {
-					return err
-				}
-				files = append(files, fi)
-			}
-		}
-		return nil
-	})
-	return
+	if m.ShowHeader && !m.places {
+		h--
+	}
+	if m.previewVisible() && m.Preview == PreviewBottom {
+		h -= m.previewHeight()
+	}
+	return h
 }
 
-func collectDirs(fsys fs.FS) ([]fs.FileInfo, error) {
-	var dirs []fs.FileInfo
-	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+func (m *Model) populate(entries []fs.FileInfo) {
+	m.entries = entries
+	files := make([]fs.FileInfo, 0, len(entries))
+	for _, fi := range entries {
+		if m.visible(fi) {
+			files = append(files, fi)
 		}
-		if path == "." {
-			return nil
-		}
-		if d.IsDir() {
-			dir, err := d.Info()
-			if err != nil {
-				return err
-			}
-			dirs = append(dirs, dir)
-			return fs.SkipDir
-		}
-		return nil
-	})
-	return dirs, err
-}
-
-func (m Model) height() int {
-	if m.ShowHelp {
-		return m.Height - 2
 	}
-	return m.Height
-}
-
-func (m *Model) populate(files []fs.FileInfo) {
+	if m.PickDir {
+		files = append(files, specialDir{"."})
+	}
+	sortFiles(files, m.Sort, m.SortDesc, m.DirsFirst)
+	if m.Tree {
+		files = m.tree(files)
+	}
+	m.all = files
 	m.files = files
 	m.st.SetMax(m.height())
+	if m.filter != "" {
+		m.applyFilter(m.filter)
+	}
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.prefillFilename()
+	return m, tea.Batch(cmd, m.updatePreview())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	// we only care about wmReadDir messages if we're not focused.
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.winWidth = msg.Width
 	case wmReadDir:
-		slog.Debug("wmReadDir", "dir", msg.dir)
-		m.populate(msg.files)
+		if msg.model != m.id {
+			break
+		}
+		slog.Debug("wmReadDir", "dir", msg.dir, "n", len(msg.files))
+		return m, m.loaded(msg)
+	case wmLoadError:
+		if msg.model != m.id {
+			break
+		}
+		slog.Debug("wmLoadError", "dir", msg.dir, "err", msg.err)
+		return m, m.loadFailed(msg)
+	case wmPreview:
+		if msg.path == m.pv.path {
+			m.pv.content = msg.content
+		}
+	case wmFileOp:
+		if msg.model != m.id {
+			break
+		}
+		return m, m.fileOpDone(msg)
+	case wmBookmarks:
+		if msg.model != m.id {
+			break
+		}
+		if msg.err != nil {
+			slog.Error("loading bookmarks", "err", msg.err)
+			break
+		}
+		return m, m.setBookmarks(msg.b)
+	case wmBookmarksSaved:
+		if msg.err != nil {
+			slog.Error("saving bookmarks", "err", msg.err)
+		}
+	case wmWatch:
+		if msg.model != m.id {
+			break
+		}
+		return m, m.watched(msg)
+	case wmExpanded:
+		if msg.model != m.id {
+			break
+		}
+		m.expandDone(msg)
 	}
 
 	if !m.focus {
@@ -179,47 +310,116 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case error:
 		slog.Error("error message", "msg", msg)
+
 	case tea.WindowSizeMsg:
 		if m.Height == 0 {
 			m.Height = msg.Height
 		}
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 	case tea.KeyMsg:
 		if !m.focus {
 			break
 		}
 		m.last = msg.String()
-		switch msg.String() {
-		case "up", "ctrl+p", "k":
+		m.opErr = nil
+		if m.confirm.active() {
+			return m.updateConfirm(msg)
+		}
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+		if m.places {
+			return m.updatePlaces(msg)
+		}
+		if m.crumbs {
+			return m.updateCrumbs(msg)
+		}
+		if m.SaveAs && m.filename.Focused() {
+			return m.updateSaveAs(msg)
+		}
+		if m.MultiSelect {
+			if cmd, ok := m.updateMarks(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.FileOps {
+			if cmd, ok := m.updateFileOps(msg); ok {
+				return m, cmd
+			}
+		}
+		if cmd, ok := m.updateBookmarks(msg); ok {
+			return m, cmd
+		}
+		switch {
+		case key.Matches(msg, m.Keys.Up):
 			m.st.Up()
-		case "down", "ctrl+n", "j":
+		case key.Matches(msg, m.Keys.Down):
 			m.st.Down(len(m.files))
-		case "right", "pgdown", "ctrl+v", "ctrl+f":
+		case m.Tree && key.Matches(msg, m.Keys.Expand):
+			return m, m.expand()
+		case m.Tree && key.Matches(msg, m.Keys.Collapse):
+			m.collapse()
+		case key.Matches(msg, m.Keys.PageDown):
 			m.st.NextPg(m.height(), len(m.files))
-		case "left", "pgup", "alt+v", "ctrl+b":
+		case key.Matches(msg, m.Keys.PageUp):
 			m.st.PrevPg(m.height())
-		case "home":
+		case key.Matches(msg, m.Keys.Home):
 			m.st.Home(m.height())
-		case "end":
+		case key.Matches(msg, m.Keys.End):
 			m.st.End(m.height(), len(m.files))
-		case "ctrl+r":
-			return m, tea.Batch(m.Init())
-		case "enter", "ctrl+m":
-			if len(m.files) == 0 {
-				break
+		case key.Matches(msg, m.Keys.Reload):
+			return m, m.load()
+		case key.Matches(msg, m.Keys.Filter):
+			return m, m.openPrompt(promptFilter, "/", m.filter)
+		case key.Matches(msg, m.Keys.Cancel):
+			m.clearFilter()
+		case key.Matches(msg, m.Keys.ToggleHidden):
+			cur := m.current()
+			m.ShowHidden = !m.ShowHidden
+			m.populate(m.entries)
+			m.focusName(cur)
+		case key.Matches(msg, m.Keys.ToggleTree):
+			cur := m.current()
+			m.Tree = !m.Tree
+			m.populate(m.entries)
+			m.focusName(cur)
+			if m.Tree {
+				return m, m.refreshTreeCmd()
 			}
-			if m.files[m.st.Cursor].IsDir() {
-				m.Directory = filepath.Join(m.Directory, m.files[m.st.Cursor].Name())
-				m.viewStack.Push(m.st)
-				m.st = display.State{}
-				return m, tea.Batch(m.Init())
+		case key.Matches(msg, m.Keys.TogglePreview):
+			m.pv.hidden = !m.pv.hidden
+			m.pv.path = "" // reload
+			m.st.Focus(m.st.Cursor, m.height(), len(m.files))
+		case key.Matches(msg, m.Keys.Sort):
+			m.Sort = m.Sort.Next()
+			m.resort()
+		case key.Matches(msg, m.Keys.SortOrder):
+			m.SortDesc = !m.SortDesc
+			m.resort()
+		case key.Matches(msg, m.Keys.Select):
+			return m.activate()
+		case key.Matches(msg, m.Keys.Filename):
+			if m.SaveAs {
+				cmds = append(cmds, m.filename.Focus())
 			}
-			cmds = append(cmds, selectedCmd(m.Directory, m.files[m.st.Cursor]))
-		case "backspace", "ctrl+h":
-			if m.viewStack.Len() > 0 {
-				m.st = m.viewStack.Pop()
-				m.Directory = filepath.Dir(m.Directory)
-				return m, tea.Batch(m.Init())
+		case key.Matches(msg, m.Keys.GoTo):
+			return m, m.openGoTo()
+		case key.Matches(msg, m.Keys.Path):
+			if m.ShowPath {
+				m.crumbs = true
+				m.crumb = len(crumbs(m.Directory)) - 1
 			}
+		case key.Matches(msg, m.Keys.PickDir):
+			if m.PickDir {
+				cmds = append(cmds, m.selectedCmd(specialDir{"."}))
+			}
+		case key.Matches(msg, m.Keys.Back):
+			return m.back()
+		case key.Matches(msg, m.Keys.NextFilter):
+			return m, m.nextFilter()
+		case key.Matches(msg, m.Keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
 		}
 		if combo := msg.String(); strings.HasPrefix(combo, "alt+") {
 			_, key, found := strings.Cut(combo, "+")
@@ -227,7 +427,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				break
 			}
 			for i, f := range m.files {
-				if strings.HasPrefix(strings.ToLower(f.Name()), key) {
+				if strings.HasPrefix(strings.ToLower(filepath.Base(f.Name())), key) {
 					if m.last == combo && i <= m.st.Cursor {
 						continue
 					}
@@ -242,12 +442,83 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func selectedCmd(dir string, fi fs.FileInfo) tea.Cmd {
-	return func() tea.Msg {
-		return WMSelected{
-			Filepath: filepath.Join(dir, fi.Name()),
-			IsDir:    fi.IsDir(),
+// visible returns true if the file should be shown in the listing.
+func (m Model) visible(fi fs.FileInfo) bool {
+	if !markable(fi) {
+		return true
+	}
+	if m.PickDir && !fi.IsDir() {
+		return false
+	}
+	if m.ShowHidden {
+		return true
+	}
+	if isHidden(fi) {
+		return false
+	}
+	return !m.ignore.Ignored(filepath.ToSlash(m.path(fi)), fi.IsDir())
+}
+
+// activate enters the directory or selects the file under cursor.
+func (m Model) activate() (Model, tea.Cmd) {
+	if len(m.files) == 0 {
+		return m, nil
+	}
+	fi := m.files[m.st.Cursor]
+	if m.PickDir && fi.Name() == "." {
+		return m, m.selectedCmd(fi)
+	}
+	if fi.IsDir() || m.isArchive(fi) {
+		m.Directory = filepath.Join(m.Directory, fi.Name())
+		m.filter = ""
+		m.viewStack.Push(m.st)
+		// entering the directory from the tree, back goes up one level
+		// at a time.
+		for range strings.Count(fi.Name(), string(filepath.Separator)) {
+			m.viewStack.Push(display.State{})
 		}
+		m.st = display.State{}
+		return m, m.loadDir()
+	}
+	if m.MultiSelect {
+		return m, m.multiSelectedCmd()
+	}
+	if m.SaveAs {
+		return m, m.save(fi.Name())
+	}
+	return m, m.selectedCmd(fi)
+}
+
+// back returns to the previous directory.
+func (m Model) back() (Model, tea.Cmd) {
+	if m.viewStack.Len() == 0 {
+		if m.err == nil || isRoot(m.Directory) {
+			return m, nil
+		}
+		// the directory the model started in can't be read.
+		cmd := m.Chdir(filepath.Dir(m.Directory))
+		return m, cmd
+	}
+	m.st = m.viewStack.Pop()
+	m.Directory = filepath.Dir(m.Directory)
+	m.filter = ""
+	return m, m.loadDir()
+}
+
+func (m Model) selectedCmd(fi fs.FileInfo) tea.Cmd {
+	msg := WMSelected{
+		Filepath: filepath.Join(m.Directory, fi.Name()),
+		IsDir:    fi.IsDir(),
+		Filter:   m.filterName(),
+	}
+	if afs, ok := m.FS.(*ArchiveFS); ok {
+		archive, entry := afs.Split(filepath.ToSlash(msg.Filepath))
+		if archive != "" {
+			msg.Archive, msg.Entry = filepath.FromSlash(archive), entry
+		}
+	}
+	return func() tea.Msg {
+		return msg
 	}
 }
 
@@ -276,25 +547,6 @@ func humanizeSize(size int64) string {
 	}
 }
 
-const Width = 40
-
-func printFile(fi fs.FileInfo) string {
-	// filename.extension  <DIR>  02-01-2006 15:04
-	const (
-		dttmLayout = "02-01-2006 15:04"
-		dirMarker  = "<DIR>"
-		filesizeSz = 6
-		dttmSz     = len(dttmLayout)
-		filenameSz = Width - filesizeSz - dttmSz - 3
-	)
-
-	var sz = dirMarker
-	if !fi.IsDir() {
-		sz = humanizeSize(fi.Size())
-	}
-	return fmt.Sprintf("%-*s %*s %s", filenameSz, display.Trunc(fi.Name(), filenameSz), filesizeSz, sz, fi.ModTime().Format(dttmLayout))
-}
-
 func (m Model) printDebug(w io.Writer) {
 	fmt.Fprintf(w, "cursor: %d\n", m.st.Cursor)
 	fmt.Fprintf(w, "min: %d\n", m.st.Min)
@@ -302,7 +554,7 @@ func (m Model) printDebug(w io.Writer) {
 	fmt.Fprintf(w, "last: %q\n", m.last)
 	fmt.Fprintf(w, "dir: %q\n", m.Directory)
 	fmt.Fprintf(w, "selected: %q\n", m.Selected)
-	for i := range Width {
+	for i := range m.width() {
 		if n := i % 10; n == 0 {
 			w.Write([]byte{'|'})
 		} else {
@@ -320,44 +572,111 @@ func (m Model) View() string {
 	if m.Debug {
 		m.printDebug(&buf)
 	}
-	if len(m.files) == 0 {
-		buf.WriteString(m.Style.Normal.Render("No files found, press [Backspace]") + "\n")
-		for i := 0; i < m.height()-1; i++ {
-			fmt.Fprintln(&buf, m.Style.Normal.Render(strings.Repeat(" ", Width-1))) //padding
-		}
+	if m.ShowPath {
+		m.printBreadcrumbs(&buf)
+	}
+	if m.places {
+		m.printPlaces(&buf)
+	} else if m.previewVisible() {
+		m.printWithPreview(&buf)
 	} else {
-		for i, file := range m.files {
-			if i < m.st.Min || i > m.st.Max {
-				continue
-			}
-			style := m.Style.Normal
-			if file.IsDir() {
-				style = m.Style.Directory
-			}
-			if i == m.st.Cursor {
-				style = m.Style.Inverted
-			}
-			fmt.Fprintln(&buf, style.Render(printFile(file)))
+		m.printListing(&buf)
+	}
+	if m.loading && len(m.files) > 0 {
+		fmt.Fprintf(&buf, "%s\n", m.Style.Normal.Render(fmt.Sprintf("Loading… %d entries", len(m.entries))))
+	}
+	if len(m.Filters) > 0 {
+		buf.WriteString(m.filterView() + "\n")
+	}
+	if m.SaveAs {
+		buf.WriteString(m.filename.View() + "\n")
+	}
+	if m.opErr != nil {
+		buf.WriteString(m.opErrView() + "\n")
+	}
+	switch {
+	case m.confirm.active():
+		buf.WriteString(m.confirmView() + "\n")
+	case m.prompt != promptNone:
+		buf.WriteString(m.input.View() + "\n")
+		if m.gotoErr != nil {
+			buf.WriteString(m.gotoErrView() + "\n")
 		}
-		numDisplayed := m.st.Displayed(len(m.files))
-		for i := 0; i < m.height()-numDisplayed; i++ {
-			fmt.Fprintln(&buf, m.Style.Normal.Render(strings.Repeat(" ", Width-1)))
+		if len(m.completions) > 0 {
+			buf.WriteString(m.completionsView() + "\n")
 		}
 	}
 	if m.ShowHelp {
-		buf.WriteString("\n ↑↓ move•[⏎] select•[⇤] back•[q] quit\n")
+		buf.WriteString("\n" + m.helpView() + "\n")
 	}
 	return buf.String()
 }
 
+func (m Model) printListing(w io.Writer) {
+	l := m.layout()
+	if m.ShowHeader {
+		io.WriteString(w, m.Style.Normal.Render(l.printHeader())+"\n")
+	}
+	if m.err != nil {
+		m.printError(w)
+		return
+	}
+	if len(m.files) == 0 {
+		msg := "No files found, press [" + m.Keys.Back.Help().Key + "]"
+		if m.loading {
+			msg = "Loading…"
+		}
+		io.WriteString(w, m.Style.Normal.Render(msg)+"\n")
+		for i := 0; i < m.height()-1; i++ {
+			fmt.Fprintln(w, m.Style.Normal.Render(strings.Repeat(" ", m.listWidth()-1))) //padding
+		}
+		return
+	}
+	for i, file := range m.files {
+		if i < m.st.Min || i > m.st.Max {
+			continue
+		}
+		style := m.Style.file(file)
+		if m.isMarked(file) {
+			style = m.Style.Marked
+		}
+		if i == m.st.Cursor {
+			style = m.Style.Inverted.Copy().Inherit(style)
+		}
+		if m.filter != "" {
+			name := displayName(file)
+			limit := len(name)
+			if trunc := display.Trunc(name, l.name); trunc != name {
+				limit = len(trunc) - len("…")
+			}
+			fmt.Fprintln(w, highlight(l.printFile(file), m.highlights[file.Name()], limit, style, m.Style.Match.Copy().Inherit(style)))
+			continue
+		}
+		fmt.Fprintln(w, style.Render(l.printFile(file)))
+	}
+	numDisplayed := m.st.Displayed(len(m.files))
+	for i := 0; i < m.height()-numDisplayed; i++ {
+		fmt.Fprintln(w, m.Style.Normal.Render(strings.Repeat(" ", m.listWidth()-1)))
+	}
+}
+
 func (m *Model) Select(filename string) {
 	if len(m.files) == 0 {
-		w, err := readFS(m.FS, m.Directory, m.Globs...)
+		mt, err := m.matcher()
+		if err != nil {
+			slog.Error("matcher", "err", err)
+			return
+		}
+		files, err := readDir(m.FS, m.Directory, mt)
 		if err != nil {
-			slog.Error("readFS", "err", err)
+			slog.Error("readDir", "err", err)
+			return
+		}
+		if m.ignore, err = loadIgnore(m.FS, m.Directory, m.IgnoreFiles...); err != nil {
+			slog.Error("loadIgnore", "err", err)
 			return
 		}
-		m.populate(w.files)
+		m.populate(files)
 	}
 	for i, f := range m.files {
 		if f.Name() == filename {
@@ -365,6 +684,10 @@ func (m *Model) Select(filename string) {
 			break
 		}
 	}
+	if m.SaveAs && filename != "" {
+		m.filename.SetValue(filepath.Base(filename))
+		m.filename.CursorEnd()
+	}
 }
 
 func (m *Model) Focus() {
@@ -375,6 +698,17 @@ func (m *Model) Blur() {
 	m.focus = false
 }
 
+// Focused returns true if the model receives the key messages.
+func (m Model) Focused() bool {
+	return m.focus
+}
+
+// Modal returns true if the model shows the prompt, question, popup or the
+// filename input, that consume all keys.
+func (m Model) Modal() bool {
+	return m.confirm.active() || m.prompt != promptNone || m.places || m.crumbs || (m.SaveAs && m.filename.Focused())
+}
+
 type specialDir struct {
 	name string
 }
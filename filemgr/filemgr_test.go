@@ -3,13 +3,14 @@ package filemgr
 import (
 	"bytes"
 	"io/fs"
-	"reflect"
 	"slices"
 	"testing"
 	"testing/fstest"
 
 	"github.com/rusq/rbubbles/display"
 
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,9 +48,14 @@ func must[T any](v T, err error) T {
 	return v
 }
 
-func Test_collectFiles(t *testing.T) {
+func globMatcher(globs ...string) *matcher {
+	return must(newMatcher(globs, false, "", nil))
+}
+
+func Test_readDir(t *testing.T) {
 	type args struct {
 		fsys  fs.FS
+		dir   string
 		globs []string
 	}
 	tests := []struct {
@@ -62,11 +68,14 @@ func Test_collectFiles(t *testing.T) {
 			name: "collect all files",
 			args: args{
 				fsys:  testfs,
+				dir:   ".",
 				globs: []string{"*"},
 			},
 			wantFiles: []fs.FileInfo{
 				must(fs.Stat(testfs, "binary1.bin")),
 				must(fs.Stat(testfs, "binary2.bin")),
+				must(fs.Stat(testfs, "dir1")),
+				must(fs.Stat(testfs, "dir2")),
 				must(fs.Stat(testfs, "file1.txt")),
 				must(fs.Stat(testfs, "file2.txt")),
 				must(fs.Stat(testfs, "file3.txt")),
@@ -77,63 +86,67 @@ func Test_collectFiles(t *testing.T) {
 			name: "collect only binary files",
 			args: args{
 				fsys:  testfs,
+				dir:   ".",
 				globs: []string{"*.bin"},
 			},
 			wantFiles: []fs.FileInfo{
 				must(fs.Stat(testfs, "binary1.bin")),
 				must(fs.Stat(testfs, "binary2.bin")),
+				must(fs.Stat(testfs, "dir1")),
+				must(fs.Stat(testfs, "dir2")),
 			},
 			wantErr: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotFiles, err := collectFiles(tt.args.fsys, tt.args.globs...)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("collectFiles() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			assert.Len(t, gotFiles, len(tt.wantFiles))
-			assert.True(t, slices.EqualFunc(tt.wantFiles, gotFiles, func(a, b fs.FileInfo) bool {
-				t.Logf("%s, %s => %v", a.Name(), b.Name(), a.Name() == b.Name())
-				return a.Name() == b.Name()
-			}))
-		})
-	}
-}
-
-func Test_collectDirs(t *testing.T) {
-	type args struct {
-		fsys fs.FS
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    []fs.FileInfo
-		wantErr bool
-	}{
 		{
-			name: "collect all dirs",
+			name: "file matching several globs",
 			args: args{
-				fsys: testfs,
+				fsys:  testfs,
+				dir:   ".",
+				globs: []string{"*.bin", "binary1.*"},
 			},
-			want: []fs.FileInfo{
+			wantFiles: []fs.FileInfo{
+				must(fs.Stat(testfs, "binary1.bin")),
+				must(fs.Stat(testfs, "binary2.bin")),
 				must(fs.Stat(testfs, "dir1")),
 				must(fs.Stat(testfs, "dir2")),
 			},
 			wantErr: false,
 		},
+		{
+			name: "subdirectory",
+			args: args{
+				fsys:  testfs,
+				dir:   "dir2",
+				globs: []string{"*"},
+			},
+			wantFiles: []fs.FileInfo{
+				specialDir{".."},
+				must(fs.Stat(testfs, "dir2/dirfile.txt")),
+			},
+			wantErr: false,
+		},
+		{
+			name: "not found",
+			args: args{
+				fsys:  testfs,
+				dir:   "nope",
+				globs: []string{"*"},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := collectDirs(tt.args.fsys)
+			gotFiles, err := readDir(tt.args.fsys, tt.args.dir, globMatcher(tt.args.globs...))
 			if (err != nil) != tt.wantErr {
-				t.Errorf("collectDirs() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("readDir() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("collectDirs() = %v, want %v", got, tt.want)
-			}
+			assert.Len(t, gotFiles, len(tt.wantFiles))
+			assert.True(t, slices.EqualFunc(tt.wantFiles, gotFiles, func(a, b fs.FileInfo) bool {
+				t.Logf("%s, %s => %v", a.Name(), b.Name(), a.Name() == b.Name())
+				return a.Name() == b.Name()
+			}))
 		})
 	}
 }
@@ -144,11 +157,11 @@ func TestModel_View(t *testing.T) {
 		if sub == "" {
 			sub = "."
 		}
-		msg, err := readFS(testfs, sub, "*")
+		files, err := readDir(testfs, sub, globMatcher("*"))
 		if err != nil {
 			t.Fatal(err)
 		}
-		return msg.files
+		return files
 	}
 
 	type fields struct {
@@ -159,6 +172,8 @@ func TestModel_View(t *testing.T) {
 		Height    int
 		ShowHelp  bool
 		Style     Style
+		Keys      KeyMap
+		help      help.Model
 		files     []fs.FileInfo
 		finished  bool
 		st        display.State
@@ -184,7 +199,7 @@ func TestModel_View(t *testing.T) {
 				},
 				files: allFiles(t, "."),
 			},
-			want: "binary1.bin         3B 01-01-0001 00:00\nbinary2.bin         3B 01-01-0001 00:00\nfile1.txt           5B 01-01-0001 00:00\nfile2.txt           5B 01-01-0001 00:00\nfile3.txt           5B 01-01-0001 00:00\ndir1             <DIR> 01-01-0001 00:00\ndir2             <DIR> 01-01-0001 00:00\n                                       \n",
+			want: "binary1.bin         3B 01-01-0001 00:00\nbinary2.bin         3B 01-01-0001 00:00\ndir1             <DIR> 01-01-0001 00:00\ndir2             <DIR> 01-01-0001 00:00\nfile1.txt           5B 01-01-0001 00:00\nfile2.txt           5B 01-01-0001 00:00\nfile3.txt           5B 01-01-0001 00:00\n                                       \n",
 		},
 		{
 			name: "finished",
@@ -229,8 +244,9 @@ func TestModel_View(t *testing.T) {
 					Max: 2,
 				},
 				files: []fs.FileInfo{},
+				Keys:  DefaultKeyMap(),
 			},
-			want: "No files found, press [Backspace]\n                                       \n",
+			want: "No files found, press [⌫]\n                                       \n",
 		},
 		{
 			name: "no files with help",
@@ -245,8 +261,10 @@ func TestModel_View(t *testing.T) {
 				},
 				files:    []fs.FileInfo{},
 				ShowHelp: true,
+				Keys:     DefaultKeyMap(),
+				help:     help.New(),
 			},
-			want: "No files found, press [Backspace]\n\n ↑↓ move•[⏎] select•[⇤] back•[q] quit\n",
+			want: "No files found, press [⌫]\n\n ↑/k up • ↓/j down • ⏎ select • ⌫ back • ? help\n",
 		},
 		{
 			name: "window height less than number of files",
@@ -274,6 +292,8 @@ func TestModel_View(t *testing.T) {
 				Height:    tt.fields.Height,
 				ShowHelp:  tt.fields.ShowHelp,
 				Style:     tt.fields.Style,
+				Keys:      tt.fields.Keys,
+				help:      tt.fields.help,
 				files:     tt.fields.files,
 				finished:  tt.fields.finished,
 				st:        tt.fields.st,
@@ -390,3 +410,23 @@ func TestModel_printDebug(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_PickDir(t *testing.T) {
+	m := New(testfs, "dir2", 10, "*")
+	m.PickDir = true
+	m.Focus()
+	m.Select("")
+	if assert.Len(t, m.files, 2) {
+		assert.Equal(t, ".", m.files[0].Name())
+		assert.Equal(t, "..", m.files[1].Name())
+	}
+
+	// enter on "." selects the current directory
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []tea.Msg{WMSelected{Filepath: "dir2", IsDir: true}}, collectMsgs(cmd))
+
+	// ctrl+d selects the current directory regardless of the cursor
+	m.st.Down(len(m.files))
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	assert.Equal(t, []tea.Msg{WMSelected{Filepath: "dir2", IsDir: true}}, collectMsgs(cmd))
+}
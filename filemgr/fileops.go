@@ -0,0 +1,204 @@
+package filemgr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// ErrReadOnly is returned by file operations if the file system of the
+// model does not implement WriteFS.
+var ErrReadOnly = errors.New("file system is read-only")
+
+// wmFileOp is sent when the file operation is finished.
+type wmFileOp struct {
+	model uint64 // model instance id
+	op    string
+	err   error
+}
+
+// WMFileOp message is sent by the file manager when the file operation is
+// finished.  Op is "mkdir", "copy", "move" or "delete", Err is nil if the
+// operation succeeded.  The parent may reload the other views of the same
+// file system.
+type WMFileOp struct {
+	Op  string
+	Err error
+}
+
+// updateFileOps processes the file operation keys.  It returns false if
+// the key is not a file operation key.
+func (m *Model) updateFileOps(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.Keys.Mkdir):
+		return m.openPrompt(promptMkdir, "Mkdir: ", ""), true
+	case key.Matches(msg, m.Keys.Copy):
+		return m.CopyTo(m.Directory), true
+	case key.Matches(msg, m.Keys.Move):
+		targets := m.targets()
+		switch {
+		case len(m.marked) > 0:
+			return m.MoveTo(m.Directory), true
+		case len(targets) == 1:
+			return m.openPrompt(promptMove, "Rename: ", path.Base(targets[0])), true
+		}
+	case key.Matches(msg, m.Keys.Delete):
+		targets := m.targets()
+		if len(targets) == 0 {
+			break
+		}
+		question := "Delete " + path.Base(targets[0]) + "?"
+		if len(targets) > 1 {
+			question = fmt.Sprintf("Delete %d files?", len(targets))
+		}
+		m.ask(question, m.fileOpCmd("delete", func(fsys WriteFS) error {
+			return forEach(targets, fsys.Remove)
+		}))
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// CopyTo opens the prompt to copy the marked files, or the file under
+// cursor, prefilled with the directory dir, relative to the root of the file
+// system.
+func (m *Model) CopyTo(dir string) tea.Cmd {
+	if len(m.targets()) == 0 {
+		return nil
+	}
+	return m.openPrompt(promptCopy, "Copy to: ", "/"+fsDir(dir))
+}
+
+// MoveTo opens the prompt to move the marked files, or the file under
+// cursor, prefilled with the directory dir, relative to the root of the file
+// system.
+func (m *Model) MoveTo(dir string) tea.Cmd {
+	if len(m.targets()) == 0 {
+		return nil
+	}
+	return m.openPrompt(promptMove, "Move to: ", "/"+fsDir(dir))
+}
+
+// targets returns the slash-separated paths of the marked files, or the
+// file under cursor, if nothing is marked.
+func (m Model) targets() []string {
+	var paths []string
+	if len(m.marked) > 0 {
+		paths = m.Marked()
+	} else if m.st.Cursor < len(m.files) && markable(m.files[m.st.Cursor]) {
+		paths = []string{m.path(m.files[m.st.Cursor])}
+	}
+	for i := range paths {
+		paths[i] = filepath.ToSlash(paths[i])
+	}
+	return paths
+}
+
+// resolve returns the slash-separated path of p entered by the user.  Paths
+// starting with "/" are relative to the root of the file system, others to
+// the current directory.
+func (m Model) resolve(p string) string {
+	if rest, ok := strings.CutPrefix(p, "/"); ok {
+		return path.Clean("./" + rest)
+	}
+	return path.Join(filepath.ToSlash(m.Directory), p)
+}
+
+// submitFileOp runs the file operation entered in the prompt.
+func (m Model) submitFileOp(kind promptKind, value string) tea.Cmd {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	var (
+		targets = m.targets()
+		dst     = m.resolve(value)
+	)
+	switch kind {
+	case promptMkdir:
+		return m.fileOpCmd("mkdir", func(fsys WriteFS) error {
+			return fsys.Mkdir(dst, 0o755)
+		})
+	case promptCopy:
+		return m.fileOpCmd("copy", func(fsys WriteFS) error {
+			return transfer(fsys, fsys.Copy, targets, dst)
+		})
+	case promptMove:
+		return m.fileOpCmd("move", func(fsys WriteFS) error {
+			return transfer(fsys, fsys.Rename, targets, dst)
+		})
+	}
+	return nil
+}
+
+// transfer copies or moves the files with the op function.  If dst is an
+// existing directory, files are placed into it, otherwise the single file
+// is copied or moved under the dst name.
+func transfer(fsys WriteFS, op func(src, dst string) error, srcs []string, dst string) error {
+	if fi, err := fs.Stat(fsys, dst); err == nil && fi.IsDir() {
+		return forEach(srcs, func(src string) error {
+			return op(src, path.Join(dst, path.Base(src)))
+		})
+	}
+	if len(srcs) > 1 {
+		return &fs.PathError{Op: "transfer", Path: dst, Err: errors.New("not a directory")}
+	}
+	return op(srcs[0], dst)
+}
+
+// forEach calls fn for each name, and returns all errors joined.
+func forEach(names []string, fn func(name string) error) error {
+	var errs error
+	for _, name := range names {
+		errs = errors.Join(errs, fn(name))
+	}
+	return errs
+}
+
+// fileOpCmd returns the command that runs the operation fn on the file
+// system of the model.
+func (m Model) fileOpCmd(op string, fn func(fsys WriteFS) error) tea.Cmd {
+	var (
+		model    = m.id
+		fsys, ok = m.FS.(WriteFS)
+	)
+	return func() tea.Msg {
+		if !ok {
+			return wmFileOp{model: model, op: op, err: ErrReadOnly}
+		}
+		return wmFileOp{model: model, op: op, err: fn(fsys)}
+	}
+}
+
+// fileOpDone handles the result of the file operation.  The error is shown
+// beneath the listing until the next key press.
+func (m *Model) fileOpDone(msg wmFileOp) tea.Cmd {
+	if msg.err != nil {
+		slog.Error("file operation", "op", msg.op, "err", msg.err)
+	}
+	m.opErr = msg.err
+	m.ClearMarks()
+	wm := WMFileOp{Op: msg.op, Err: msg.err}
+	return tea.Batch(m.load(), func() tea.Msg {
+		return wm
+	})
+}
+
+// opErrView returns the line with the error of the last file operation.
+func (m Model) opErrView() string {
+	return m.Style.Error.Render(display.Trunc(m.opErr.Error(), m.width()-1))
+}
+
+// Reload returns the command that rereads the current directory.
+func (m Model) Reload() tea.Cmd {
+	return m.load()
+}
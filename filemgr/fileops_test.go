@@ -0,0 +1,108 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_resolve(t *testing.T) {
+	m := Model{Directory: "dir2"}
+	assert.Equal(t, "dir2/sub", m.resolve("sub"))
+	assert.Equal(t, ".", m.resolve(".."))
+	assert.Equal(t, "dir1", m.resolve("/dir1"))
+	assert.Equal(t, ".", m.resolve("/"))
+}
+
+// run executes the command and feeds the resulting messages back to the
+// model until there are no more commands.
+func run(m Model, cmd tea.Cmd) Model {
+	for _, msg := range collectMsgs(cmd) {
+		m, cmd = m.Update(msg)
+		m = run(m, cmd)
+	}
+	return m
+}
+
+func TestModel_fileOps(t *testing.T) {
+	fsys := NewMemFS()
+	require.NoError(t, fsys.WriteFile("a.txt", []byte("a"), 0o644))
+	require.NoError(t, fsys.WriteFile("b.txt", []byte("b"), 0o644))
+	require.NoError(t, fsys.Mkdir("dir", 0o755))
+
+	m := New(fsys, ".", 10, "*")
+	m.FileOps = true
+	m.MultiSelect = true
+	m.Focus()
+	m.Select("")
+
+	key := func(m Model, msg tea.KeyMsg) Model {
+		m, cmd := m.Update(msg)
+		return run(m, cmd)
+	}
+
+	// mark all files and move them into dir.
+	m.markGlob("*.txt", true)
+	m = key(m, tea.KeyMsg{Type: tea.KeyF6})
+	m.input.SetValue("dir")
+	m = key(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Empty(t, m.Marked())
+	_, err := fs.Stat(fsys, "dir/a.txt")
+	assert.NoError(t, err)
+	_, err = fs.Stat(fsys, "a.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// delete dir
+	m.Select("dir")
+	m = key(m, tea.KeyMsg{Type: tea.KeyF8})
+	assert.True(t, m.confirm.active())
+	m = key(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	_, err = fs.Stat(fsys, "dir")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Empty(t, m.files)
+}
+
+func TestModel_fileOpError(t *testing.T) {
+	fsys := NewMemFS()
+	require.NoError(t, fsys.Mkdir("dir", 0o755))
+	require.NoError(t, fsys.WriteFile("a.txt", []byte("a"), 0o644))
+
+	m := New(fsys, ".", 10, "*")
+	m.FileOps = true
+	m.Focus()
+	m.Select("")
+	other := New(fsys, ".", 10, "*")
+	other.MultiSelect = true
+	other.Select("a.txt")
+	other.markGlob("*.txt", true)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyF7})
+	m.input.SetValue("dir")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msgs := collectMsgs(cmd)
+	require.Len(t, msgs, 1)
+	done := msgs[0]
+
+	// the other model ignores the operation
+	other, _ = other.Update(done)
+	assert.Equal(t, []string{"a.txt"}, other.Marked())
+
+	// the error is shown and reported
+	m, cmd = m.Update(done)
+	assert.Contains(t, m.View(), "mkdir dir: file already exists")
+	var got WMFileOp
+	for _, msg := range collectMsgs(cmd) {
+		if wm, ok := msg.(WMFileOp); ok {
+			got = wm
+		}
+	}
+	assert.Equal(t, "mkdir", got.Op)
+	assert.ErrorIs(t, got.Err, fs.ErrExist)
+
+	// until the next key
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.NotContains(t, m.View(), "file already exists")
+}
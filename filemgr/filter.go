@@ -0,0 +1,112 @@
+package filemgr
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// applyFilter narrows the listing to the files that fuzzy-match the filter
+// and places the cursor on the best match.  Empty filter restores the full
+// listing.
+func (m *Model) applyFilter(filter string) {
+	m.filter = filter
+	clear(m.highlights)
+	if filter == "" {
+		cur := m.current()
+		m.files = m.all
+		m.focusName(cur)
+		return
+	}
+	var (
+		files     = make([]fs.FileInfo, 0, len(m.all))
+		best      = -1
+		bestScore int
+	)
+	if m.highlights == nil {
+		m.highlights = make(map[string][]int)
+	}
+	for _, fi := range m.all {
+		if !markable(fi) {
+			continue // never match ".."
+		}
+		score, idx, ok := fuzzyMatch(filter, filepath.Base(fi.Name()))
+		if !ok {
+			continue
+		}
+		if n, ok := fi.(treeNode); ok {
+			for i := range idx {
+				idx[i] += len(n.prefix) // past the tree guides
+			}
+		}
+		if best < 0 || score > bestScore {
+			best, bestScore = len(files), score
+		}
+		m.highlights[fi.Name()] = idx
+		files = append(files, fi)
+	}
+	m.files = files
+	m.st.Focus(max(best, 0), m.height(), len(m.files))
+}
+
+// clearFilter removes the filter, if set, restoring the full listing.
+func (m *Model) clearFilter() {
+	if m.filter != "" {
+		m.applyFilter("")
+	}
+}
+
+// current returns the name of the file under cursor or an empty string.
+func (m Model) current() string {
+	if m.st.Cursor < len(m.files) {
+		return m.files[m.st.Cursor].Name()
+	}
+	return ""
+}
+
+// focusName places the cursor on the file with the given name, if it is
+// present in the listing, otherwise it makes sure that the cursor is within
+// the listing.
+func (m *Model) focusName(name string) {
+	if m.current() == name && name != "" {
+		return
+	}
+	for i, fi := range m.files {
+		if fi.Name() == name {
+			m.st.Focus(i, m.height(), len(m.files))
+			return
+		}
+	}
+	m.st.Focus(min(m.st.Cursor, max(len(m.files)-1, 0)), m.height(), len(m.files))
+}
+
+// highlight renders the line, using the hl style for the bytes at idx
+// offsets, and style for the rest.  Offsets beyond limit are not
+// highlighted.
+func highlight(line string, idx []int, limit int, style, hl lipgloss.Style) string {
+	if len(idx) == 0 {
+		return style.Render(line)
+	}
+	var (
+		buf  strings.Builder
+		from int
+	)
+	for _, i := range idx {
+		if i >= limit || i < from {
+			break
+		}
+		_, sz := utf8.DecodeRuneInString(line[i:])
+		if from < i {
+			buf.WriteString(style.Render(line[from:i]))
+		}
+		buf.WriteString(hl.Render(line[i : i+sz]))
+		from = i + sz
+	}
+	if from < len(line) {
+		buf.WriteString(style.Render(line[from:]))
+	}
+	return buf.String()
+}
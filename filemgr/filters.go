@@ -0,0 +1,48 @@
+package filemgr
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NamedFilter is the named set of file patterns, such as "JSON (*.json)",
+// that the user can switch to, like the file type in the file dialog.
+type NamedFilter struct {
+	Name  string
+	Globs []string
+}
+
+// globs returns the patterns of the active named filter, or Globs, if
+// there are no named filters.
+func (m Model) globs() []string {
+	if f, ok := m.activeFilter(); ok {
+		return f.Globs
+	}
+	return m.Globs
+}
+
+func (m Model) activeFilter() (NamedFilter, bool) {
+	if m.ActiveFilter < 0 || len(m.Filters) <= m.ActiveFilter {
+		return NamedFilter{}, false
+	}
+	return m.Filters[m.ActiveFilter], true
+}
+
+// filterName returns the name of the active named filter, or an empty
+// string.
+func (m Model) filterName() string {
+	f, _ := m.activeFilter()
+	return f.Name
+}
+
+// nextFilter switches to the next named filter and reloads the directory.
+func (m *Model) nextFilter() tea.Cmd {
+	if len(m.Filters) < 2 {
+		return nil
+	}
+	m.ActiveFilter = (m.ActiveFilter + 1) % len(m.Filters)
+	return m.load()
+}
+
+func (m Model) filterView() string {
+	return m.Style.Normal.Render("Type: " + m.filterName())
+}
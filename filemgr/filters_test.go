@@ -0,0 +1,38 @@
+package filemgr
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Filters(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Filters = []NamedFilter{
+		{Name: "Binary (*.bin)", Globs: []string{"*.bin"}},
+		{Name: "All files (*)", Globs: []string{"*"}},
+	}
+	m.Focus()
+	m = run(m, m.load())
+	assert.Len(t, m.files, 4) // 2 dirs and 2 binaries
+	assert.Contains(t, m.View(), "Type: Binary (*.bin)\n")
+	assert.Equal(t, ".bin", m.defaultExt())
+
+	// cycle to the next filter
+	m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}} })
+	assert.Equal(t, 1, m.ActiveFilter)
+	assert.Len(t, m.files, 7)
+	assert.Contains(t, m.View(), "Type: All files (*)\n")
+
+	// selection reports the filter
+	m.Select("file1.txt")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, collectMsgs(cmd), WMSelected{Filepath: "file1.txt", Filter: "All files (*)"})
+
+	// clicking the filter line cycles back
+	m = run(m, func() tea.Msg {
+		return tea.MouseMsg{Y: m.filterRow(), Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	})
+	assert.Equal(t, 0, m.ActiveFilter)
+}
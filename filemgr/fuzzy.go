@@ -0,0 +1,69 @@
+package filemgr
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// scoring weights for fuzzyMatch.
+const (
+	fzMatch       = 1 // each matched character
+	fzConsecutive = 5 // matched character follows the previous match
+	fzBoundary    = 8 // matched character starts a word
+	fzFirst       = 3 // first character of the string matched
+	fzGap         = 1 // penalty for each skipped character
+)
+
+// fuzzyMatch matches the pattern against s as a case-insensitive
+// subsequence.  It returns the score of the match, the byte offsets of the
+// matched characters in s and true, if all characters of the pattern were
+// found.  The higher the score, the better the match.
+func fuzzyMatch(pattern, s string) (score int, idx []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+	var (
+		pr, _   = utf8.DecodeRuneInString(pattern)
+		prev    rune // previous rune of s
+		matched bool // previous rune of s was matched
+	)
+	for i, r := range s {
+		if pattern == "" {
+			break
+		}
+		if unicode.ToLower(r) != unicode.ToLower(pr) {
+			if len(idx) > 0 {
+				score -= fzGap
+			}
+			prev, matched = r, false
+			continue
+		}
+		score += fzMatch
+		switch {
+		case i == 0:
+			score += fzFirst + fzBoundary
+		case matched:
+			score += fzConsecutive
+		case isBoundary(prev, r):
+			score += fzBoundary
+		}
+		idx = append(idx, i)
+		pattern = pattern[utf8.RuneLen(pr):]
+		pr, _ = utf8.DecodeRuneInString(pattern)
+		prev, matched = r, true
+	}
+	if pattern != "" {
+		return 0, nil, false
+	}
+	return score, idx, true
+}
+
+// isBoundary returns true if r starts a new word after prev.
+func isBoundary(prev, r rune) bool {
+	switch prev {
+	case '_', '-', '.', ' ', '/':
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(r) ||
+		!unicode.IsDigit(prev) && unicode.IsDigit(r)
+}
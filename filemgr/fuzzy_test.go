@@ -0,0 +1,79 @@
+package filemgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fuzzyMatch(t *testing.T) {
+	type args struct {
+		pattern string
+		s       string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantIdx []int
+		wantOk  bool
+	}{
+		{
+			name:    "empty pattern",
+			args:    args{"", "file1.txt"},
+			wantIdx: nil,
+			wantOk:  true,
+		},
+		{
+			name:    "subsequence",
+			args:    args{"ftx", "file1.txt"},
+			wantIdx: []int{0, 6, 7},
+			wantOk:  true,
+		},
+		{
+			name:    "case insensitive",
+			args:    args{"FILE", "file1.txt"},
+			wantIdx: []int{0, 1, 2, 3},
+			wantOk:  true,
+		},
+		{
+			name:    "no match",
+			args:    args{"xyz", "file1.txt"},
+			wantIdx: nil,
+			wantOk:  false,
+		},
+		{
+			name:    "multibyte",
+			args:    args{"яб", "яблоко"},
+			wantIdx: []int{0, 2},
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, gotIdx, gotOk := fuzzyMatch(tt.args.pattern, tt.args.s)
+			assert.Equal(t, tt.wantIdx, gotIdx)
+			assert.Equal(t, tt.wantOk, gotOk)
+		})
+	}
+}
+
+func Test_fuzzyMatchScore(t *testing.T) {
+	// consecutive and word-boundary matches should score better.
+	better, _, _ := fuzzyMatch("exp", "export.zip")
+	worse, _, _ := fuzzyMatch("exp", "some_example.zip")
+	assert.Greater(t, better, worse)
+}
+
+func TestModel_applyFilter(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Select("")
+	m.applyFilter("f2")
+	if assert.Len(t, m.files, 1) {
+		assert.Equal(t, "file2.txt", m.files[0].Name())
+	}
+	assert.Equal(t, 0, m.st.Cursor)
+
+	m.applyFilter("")
+	assert.Len(t, m.files, len(m.all))
+	assert.Equal(t, "file2.txt", m.current(), "cursor should stay on the file")
+}
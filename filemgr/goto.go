@@ -0,0 +1,141 @@
+package filemgr
+
+import (
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// maxCompletions is the number of the completion candidates shown beneath
+// the "go to" prompt.
+const maxCompletions = 32
+
+// openGoTo opens the prompt for the path to go to, prefilled with the
+// current directory.
+func (m *Model) openGoTo() tea.Cmd {
+	dir := "/"
+	if d := fsDir(m.Directory); d != "." {
+		dir += d + "/"
+	}
+	return m.openPrompt(promptGoTo, "Go to: ", dir)
+}
+
+// goTo changes the directory to the entered path, that is relative to the
+// current directory, or to the root of the FS, if it starts with "/".  If
+// the path is a file, the cursor is placed on it.  It returns an error, if
+// the path doesn't exist.
+func (m *Model) goTo(value string) (tea.Cmd, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	p := m.resolve(value)
+	fi, err := fs.Stat(m.FS, p)
+	if err != nil {
+		slog.Error("go to", "path", p, "err", err)
+		return nil, err
+	}
+	if fi.IsDir() || m.isArchive(fi) {
+		return m.Chdir(p), nil
+	}
+	cmd := m.Chdir(path.Dir(p))
+	m.reveal = path.Base(p)
+	return cmd, nil
+}
+
+// complete completes the last element of the path in the prompt to the
+// longest common prefix of the matching names.  Directories are completed
+// with the trailing slash.  If the completion is ambiguous, the candidates
+// are shown beneath the prompt.
+func (m *Model) complete() {
+	value := m.input.Value()
+	dir, prefix := path.Split(value)
+	m.completions, m.gotoErr = nil, nil
+	names, err := m.candidates(dir, prefix)
+	if err != nil {
+		slog.Debug("complete", "dir", dir, "err", err)
+		return
+	}
+	switch len(names) {
+	case 0:
+		return
+	case 1:
+		m.input.SetValue(dir + names[0])
+	default:
+		common := names[0]
+		for _, name := range names[1:] {
+			common = commonPrefix(common, name)
+		}
+		if len(common) > len(prefix) {
+			m.input.SetValue(dir + common)
+		} else {
+			m.completions = names
+		}
+	}
+	m.input.CursorEnd()
+}
+
+// candidates returns the names in the directory dir, as entered in the
+// prompt, starting with prefix.  Directories have the trailing slash.
+func (m Model) candidates(dir, prefix string) ([]string, error) {
+	entries, err := fs.ReadDir(m.FS, fsDir(m.resolve(dir)))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, de := range entries {
+		name := de.Name()
+		if !hasPrefix(name, prefix, m.IgnoreCase) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") && !m.ShowHidden {
+			continue
+		}
+		if de.IsDir() {
+			name += "/"
+		} else if fi, err := de.Info(); err == nil && m.isArchive(fi) {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func hasPrefix(s, prefix string, fold bool) bool {
+	if fold {
+		return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+	}
+	return strings.HasPrefix(s, prefix)
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	for n > 0 && n < len(a) && !utf8.RuneStart(a[n]) {
+		n-- // don't split the rune
+	}
+	return a[:n]
+}
+
+// completionsView returns the line with the completion candidates.
+func (m Model) completionsView() string {
+	names := m.completions
+	if len(names) > maxCompletions {
+		names = names[:maxCompletions]
+	}
+	return m.Style.Normal.Render(display.Trunc(strings.Join(names, "  "), m.width()-1))
+}
+
+// gotoErrView returns the line with the error of the last "go to".
+func (m Model) gotoErrView() string {
+	return m.Style.Error.Render(display.Trunc(m.gotoErr.Error(), m.width()-1))
+}
@@ -0,0 +1,109 @@
+package filemgr
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_goTo(t *testing.T) {
+	press := func(m Model, msg tea.KeyMsg) Model {
+		m, cmd := m.Update(msg)
+		if msg.Type == tea.KeyEnter || msg.Type == tea.KeyBackspace {
+			m = run(m, cmd) // load the directory, skip the cursor blinking
+		}
+		return m
+	}
+	typ := func(m Model, s string) Model {
+		return press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	m := New(treefs, ".", 10, "*")
+	m.Focus()
+	m = run(m, m.load())
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	assert.True(t, m.Modal())
+	assert.Equal(t, "/", m.input.Value())
+
+	// unique completion
+	m = typ(m, "a")
+	m = press(m, tab)
+	assert.Equal(t, "/a/", m.input.Value())
+	m = press(m, tab)
+	assert.Equal(t, "/a/", m.input.Value(), "ambiguous")
+	assert.Contains(t, m.View(), "b/  x.txt")
+
+	m = typ(m, "b")
+	m = press(m, tab)
+	assert.Equal(t, "/a/b/", m.input.Value())
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.Modal())
+	assert.Equal(t, "a/b", m.Directory)
+	assert.Equal(t, 2, m.viewStack.Len())
+
+	// backspace walks up
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "a", m.Directory)
+
+	// relative path to a file places the cursor on it
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlU}) // clear the input
+	m = typ(m, "../c/y.txt")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "c", m.Directory)
+	assert.Equal(t, "y.txt", m.current())
+
+	// the prompt stays open on the missing path
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = typ(m, "nope")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, m.Modal())
+	assert.Equal(t, "c", m.Directory)
+	assert.Error(t, m.gotoErr)
+	assert.Contains(t, m.View(), "nope")
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.NoError(t, m.gotoErr, "cleared on edit")
+}
+
+func TestModel_goToLines(t *testing.T) {
+	m := New(treefs, ".", 10, "*")
+	m.ShowHelp = true
+	m.Focus()
+	m = run(m, m.load())
+	m.openGoTo()
+	m.input.SetValue("/a/")
+	helpLine := func(m Model) {
+		t.Helper()
+		// the help line is the last one, followed by the newline
+		assert.Equal(t, strings.Count(m.View(), "\n")-1, m.helpRow())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, []string{"b/", "x.txt"}, m.completions)
+	helpLine(m)
+
+	// typing clears the candidates
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.Empty(t, m.completions)
+	helpLine(m)
+
+	// so does the completion in the missing directory
+	m.completions = []string{"stale"}
+	m.input.SetValue("/nope/")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Empty(t, m.completions)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Error(t, m.gotoErr)
+	helpLine(m)
+}
+
+func Test_commonPrefix(t *testing.T) {
+	assert.Equal(t, "ab", commonPrefix("abc", "abd"))
+	assert.Equal(t, "", commonPrefix("x", "y"))
+	assert.Equal(t, "a", commonPrefix("aé", "aè"), "runes are not split")
+}
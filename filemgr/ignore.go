@@ -0,0 +1,162 @@
+package filemgr
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultIgnoreFiles are the ignore files honoured by the model created with
+// New.
+var DefaultIgnoreFiles = []string{".gitignore"}
+
+// ignoreRule is a single pattern from a .gitignore-style file.
+type ignoreRule struct {
+	base     string // directory of the ignore file, relative to the FS root
+	pattern  string
+	negate   bool // pattern starts with "!"
+	dirOnly  bool // pattern ends with "/"
+	anchored bool // pattern is matched against the path relative to base
+}
+
+// ignoreRules is the list of ignore rules in the order of precedence, the
+// last matching rule wins.
+type ignoreRules []ignoreRule
+
+// loadIgnore reads the ignore files with the given names from dir and all
+// its parent directories up to the root of fsys.
+func loadIgnore(fsys fs.FS, dir string, names ...string) (ignoreRules, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var rules ignoreRules
+	for _, d := range ancestors(dir) {
+		for _, name := range names {
+			data, err := fs.ReadFile(fsys, path.Join(d, name))
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			rules = append(rules, parseIgnore(d, data)...)
+		}
+	}
+	return rules, nil
+}
+
+// ancestors returns the list of directories from the root "." to dir,
+// inclusive.
+func ancestors(dir string) []string {
+	dir = strings.TrimPrefix(path.Clean(filepath.ToSlash(dir)), "/")
+	dirs := []string{"."}
+	if dir == "." || dir == "" {
+		return dirs
+	}
+	for i, c := range dir {
+		if c == '/' {
+			dirs = append(dirs, dir[:i])
+		}
+	}
+	return append(dirs, dir)
+}
+
+// parseIgnore parses the contents of the ignore file located in the base
+// directory.
+func parseIgnore(base string, data []byte) ignoreRules {
+	var rules ignoreRules
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		r := ignoreRule{base: base}
+		if line[0] == '!' {
+			r.negate = true
+			line = line[1:]
+		} else if line[0] == '\\' {
+			line = line[1:] // escaped "#" or "!"
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.pattern = line
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// Ignored returns true if the file at name, relative to the FS root, should
+// be ignored.
+func (rules ignoreRules) Ignored(name string, isDir bool) bool {
+	var ignored bool
+	for _, r := range rules {
+		if r.match(name, isDir) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) match(name string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel := name
+	if r.base != "." {
+		var ok bool
+		if rel, ok = strings.CutPrefix(name, r.base+"/"); !ok {
+			return false
+		}
+	}
+	if r.anchored {
+		return matchPath(r.pattern, rel)
+	}
+	return matchPath(r.pattern, path.Base(rel))
+}
+
+// matchPath matches the slash-separated name against the pattern.  In
+// addition to the path.Match syntax, the pattern may contain "**" segments
+// that match zero or more path segments.  Malformed patterns never match.
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// isHidden returns true if the file is a dotfile.
+func isHidden(fi fs.FileInfo) bool {
+	return markable(fi) && strings.HasPrefix(filepath.Base(fi.Name()), ".")
+}
@@ -0,0 +1,94 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ignorefs = fstest.MapFS{
+	".gitignore":            &fstest.MapFile{Data: []byte("# comment\nnode_modules/\n/build\n*.log\n!keep.log\n")},
+	".git/HEAD":             &fstest.MapFile{Data: []byte("ref: refs/heads/master")},
+	"build/out.bin":         &fstest.MapFile{},
+	"node_modules/x/x.js":   &fstest.MapFile{},
+	"debug.log":             &fstest.MapFile{},
+	"keep.log":              &fstest.MapFile{},
+	"main.go":               &fstest.MapFile{},
+	"sub/.gitignore":        &fstest.MapFile{Data: []byte("*.tmp\n")},
+	"sub/build/x":           &fstest.MapFile{},
+	"sub/node_modules/x.js": &fstest.MapFile{},
+	"sub/a.tmp":             &fstest.MapFile{},
+	"sub/b.txt":             &fstest.MapFile{},
+}
+
+func Test_matchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "sub/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/main.go", true},
+		{"a/**", "a/b/c", true},
+		{"a/**/c", "a/c", true},
+		{"a/**/c", "a/b/d/c", true},
+		{"a/**/c", "a/b/d", false},
+		{"[", "[", false}, // malformed
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPath(tt.pattern, tt.name))
+		})
+	}
+}
+
+func Test_loadIgnore(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		file  string
+		isDir bool
+		want  bool
+	}{
+		{"dir only pattern", ".", "node_modules", true, true},
+		{"dir only pattern on file", ".", "node_modules", false, false},
+		{"dir only pattern in subdir", "sub", "sub/node_modules", true, true},
+		{"anchored", ".", "build", true, true},
+		{"anchored in subdir", "sub", "sub/build", true, false},
+		{"glob", ".", "debug.log", false, true},
+		{"negated", ".", "keep.log", false, false},
+		{"nested ignore file", "sub", "sub/a.tmp", false, true},
+		{"not ignored", "sub", "sub/b.txt", false, false},
+		{"nested rules do not apply to parent", ".", "a.tmp", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadIgnore(ignorefs, tt.dir, ".gitignore")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, rules.Ignored(tt.file, tt.isDir))
+		})
+	}
+}
+
+func TestModel_visible(t *testing.T) {
+	names := func(ff []fs.FileInfo) []string {
+		var ss []string
+		for _, fi := range ff {
+			ss = append(ss, fi.Name())
+		}
+		return ss
+	}
+	m := New(ignorefs, ".", 20, "*")
+	m.Select("")
+	assert.Equal(t, []string{"sub", "keep.log", "main.go"}, names(m.files))
+
+	m.ShowHidden = true
+	m.populate(m.entries)
+	assert.Equal(t, []string{".git", "build", "node_modules", "sub", ".gitignore", "debug.log", "keep.log", "main.go"}, names(m.files))
+}
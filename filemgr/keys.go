@@ -0,0 +1,177 @@
+package filemgr
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeyMap defines the key bindings of the file manager.  Bindings can be
+// changed or disabled by the caller, the help line reflects the changes.
+type KeyMap struct {
+	// Navigation.
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+	Select   key.Binding
+	Back     key.Binding
+	Cancel   key.Binding
+	Reload   key.Binding
+	// View.
+	Filter        key.Binding
+	ToggleHidden  key.Binding
+	TogglePreview key.Binding
+	Sort          key.Binding
+	SortOrder     key.Binding
+	NextFilter    key.Binding
+	ToggleTree    key.Binding
+	Help          key.Binding
+	// Tree mode, Expand and Collapse take precedence over PageDown and
+	// PageUp.
+	Expand   key.Binding
+	Collapse key.Binding
+	// Path bar, Left and Right move between the path segments.
+	Path  key.Binding
+	Left  key.Binding
+	Right key.Binding
+	// Go to the entered path, Complete completes it.
+	GoTo     key.Binding
+	Complete key.Binding
+	// Directory picking and saving.
+	PickDir  key.Binding
+	Filename key.Binding
+	// Multi-select.
+	Mark       key.Binding
+	MarkAll    key.Binding
+	InvertMark key.Binding
+	MarkGlob   key.Binding
+	UnmarkGlob key.Binding
+	// File operations.
+	Copy   key.Binding
+	Move   key.Binding
+	Mkdir  key.Binding
+	Delete key.Binding
+	// Bookmarks.  Each key of Pinned jumps to the pinned bookmark with the
+	// same index.
+	Pin    key.Binding
+	Places key.Binding
+	Pinned key.Binding
+	// Confirmation.
+	Yes key.Binding
+	No  key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:       key.NewBinding(key.WithKeys("up", "ctrl+p", "k"), key.WithHelp("↑/k", "up")),
+		Down:     key.NewBinding(key.WithKeys("down", "ctrl+n", "j"), key.WithHelp("↓/j", "down")),
+		PageUp:   key.NewBinding(key.WithKeys("left", "pgup", "alt+v", "ctrl+b"), key.WithHelp("←/pgup", "page up")),
+		PageDown: key.NewBinding(key.WithKeys("right", "pgdown", "ctrl+v", "ctrl+f"), key.WithHelp("→/pgdn", "page down")),
+		Home:     key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "first")),
+		End:      key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "last")),
+		Select:   key.NewBinding(key.WithKeys("enter", "ctrl+m"), key.WithHelp("⏎", "select")),
+		Back:     key.NewBinding(key.WithKeys("backspace", "ctrl+h"), key.WithHelp("⌫", "back")),
+		Cancel:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		Reload:   key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reload")),
+
+		Filter:        key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		ToggleHidden:  key.NewBinding(key.WithKeys("."), key.WithHelp(".", "hidden files")),
+		TogglePreview: key.NewBinding(key.WithKeys("f3"), key.WithHelp("f3", "preview")),
+		Sort:          key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort by")),
+		SortOrder:     key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "sort order")),
+		NextFilter:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "file type")),
+		ToggleTree:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "tree")),
+		Help:          key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+
+		Expand:   key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "expand")),
+		Collapse: key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")),
+
+		Path:  key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "go up to…")),
+		Left:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "parent")),
+		Right: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "child")),
+
+		GoTo:     key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "go to…")),
+		Complete: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "complete")),
+
+		PickDir:  key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "pick directory")),
+		Filename: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "file name")),
+
+		Mark:       key.NewBinding(key.WithKeys(" ", "insert"), key.WithHelp("space", "mark")),
+		MarkAll:    key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("ctrl+a", "mark all")),
+		InvertMark: key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "invert marks")),
+		MarkGlob:   key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "mark…")),
+		UnmarkGlob: key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "unmark…")),
+
+		Copy:   key.NewBinding(key.WithKeys("f5"), key.WithHelp("f5", "copy")),
+		Move:   key.NewBinding(key.WithKeys("f6"), key.WithHelp("f6", "move")),
+		Mkdir:  key.NewBinding(key.WithKeys("f7"), key.WithHelp("f7", "mkdir")),
+		Delete: key.NewBinding(key.WithKeys("f8", "delete"), key.WithHelp("f8", "delete")),
+
+		Pin:    key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "pin")),
+		Places: key.NewBinding(key.WithKeys("'"), key.WithHelp("'", "bookmarks")),
+		Pinned: key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "go to pinned")),
+
+		Yes: key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "yes")),
+		No:  key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n", "no")),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select, k.Back, k.Help}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Home, k.End},
+		{k.Select, k.Back, k.Reload, k.Path, k.GoTo, k.PickDir, k.Filename},
+		{k.Filter, k.ToggleHidden, k.TogglePreview, k.Sort, k.SortOrder, k.NextFilter, k.ToggleTree, k.Help},
+		{k.Expand, k.Collapse},
+		{k.Mark, k.MarkAll, k.InvertMark, k.MarkGlob, k.UnmarkGlob},
+		{k.Copy, k.Move, k.Mkdir, k.Delete},
+		{k.Pin, k.Places, k.Pinned},
+	}
+}
+
+// helpIndent is the left padding of the help.
+const helpIndent = 1
+
+// helpView renders the help for the active key bindings.
+func (m Model) helpView() string {
+	return lipgloss.NewStyle().PaddingLeft(helpIndent).Render(m.help.View(m.activeKeys()))
+}
+
+// activeKeys returns the key map with the bindings, that are not available
+// in the current mode, disabled.
+func (m Model) activeKeys() KeyMap {
+	k := m.Keys
+	k.Path.SetEnabled(k.Path.Enabled() && m.ShowPath)
+	k.PickDir.SetEnabled(k.PickDir.Enabled() && m.PickDir)
+	k.Filename.SetEnabled(k.Filename.Enabled() && m.SaveAs)
+	k.NextFilter.SetEnabled(k.NextFilter.Enabled() && len(m.Filters) > 1)
+	k.Expand.SetEnabled(k.Expand.Enabled() && m.Tree)
+	k.Collapse.SetEnabled(k.Collapse.Enabled() && m.Tree)
+	for _, b := range []*key.Binding{&k.Mark, &k.MarkAll, &k.InvertMark, &k.MarkGlob, &k.UnmarkGlob} {
+		b.SetEnabled(b.Enabled() && m.MultiSelect)
+	}
+	for _, b := range []*key.Binding{&k.Copy, &k.Move, &k.Mkdir, &k.Delete} {
+		b.SetEnabled(b.Enabled() && m.FileOps)
+	}
+	for _, b := range []*key.Binding{&k.Pin, &k.Places, &k.Pinned} {
+		b.SetEnabled(b.Enabled() && m.Store != nil)
+	}
+	return k
+}
+
+// pinnedIndex returns the index of the pinned bookmark, starting from 1, for
+// the key of the Pinned binding.
+func (k KeyMap) pinnedIndex(msg tea.KeyMsg) int {
+	return slices.Index(k.Pinned.Keys(), msg.String()) + 1
+}
@@ -0,0 +1,90 @@
+package filemgr
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Keys(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Focus()
+	m = run(m, m.load())
+
+	// rebinding replaces the default keys
+	m.Keys.Down = key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "down"))
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "dir1", m.current())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	assert.Equal(t, "dir2", m.current())
+
+	// pinned bookmarks are indexed by the key position
+	m.Keys.Pinned.SetKeys("alt+1", "alt+2")
+	assert.Equal(t, 2, m.Keys.pinnedIndex(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}, Alt: true}))
+	assert.Equal(t, 0, m.Keys.pinnedIndex(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}}))
+}
+
+func TestModel_helpView(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.ShowHelp = true
+	m.Focus()
+	assert.Equal(t, " ↑/k up • ↓/j down • ⏎ select • ⌫ back • ? help", m.helpView())
+
+	// the help reflects the bindings
+	m.Keys.Back.SetHelp("⇤", "go back")
+	m.Keys.Up.SetEnabled(false)
+	assert.Equal(t, " ↓/j down • ⏎ select • ⇤ go back • ? help", m.helpView())
+
+	// full help shows only the keys available in the current mode
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	assert.NotContains(t, m.helpView(), "mark all")
+	m.MultiSelect = true
+	assert.Contains(t, m.helpView(), "mark all")
+	assert.Less(t, m.height(), 10-2)
+}
+
+func TestModel_hintKeys(t *testing.T) {
+	m := New(fstest.MapFS{}, ".", 10, "*")
+	m.Keys.Back.SetHelp("⇤", "go back")
+	m.Keys.Pin.SetHelp("p", "pin")
+	m.Focus()
+	m = run(m, m.load())
+	assert.Contains(t, m.View(), "No files found, press [⇤]")
+
+	var buf strings.Builder
+	m.bookmarks = Bookmarks{}
+	m.printPlaces(&buf)
+	assert.Contains(t, buf.String(), "press [p] to pin")
+}
+
+func Test_keyMsg(t *testing.T) {
+	tests := []struct {
+		name string
+		want tea.Key
+	}{
+		{"enter", tea.Key{Type: tea.KeyEnter}},
+		{"backspace", tea.Key{Type: tea.KeyBackspace}},
+		{"esc", tea.Key{Type: tea.KeyEsc}},
+		{"ctrl+r", tea.Key{Type: tea.KeyCtrlR}},
+		{"up", tea.Key{Type: tea.KeyUp}},
+		{"pgdown", tea.Key{Type: tea.KeyPgDown}},
+		{"delete", tea.Key{Type: tea.KeyDelete}},
+		{" ", tea.Key{Type: tea.KeySpace}},
+		{"f3", tea.Key{Type: tea.KeyF3}},
+		{"alt+v", tea.Key{Type: tea.KeyRunes, Runes: []rune("v"), Alt: true}},
+		{"alt+enter", tea.Key{Type: tea.KeyEnter, Alt: true}},
+		{"k", tea.Key{Type: tea.KeyRunes, Runes: []rune("k")}},
+		{"?", tea.Key{Type: tea.KeyRunes, Runes: []rune("?")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keyMsg(tt.name)
+			assert.Equal(t, tea.KeyMsg(tt.want), got)
+			assert.Equal(t, tt.name, got.String())
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package filemgr
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// DefaultWidth is the width of the view, if neither Model.Width, nor the
+// window width is known.
+const DefaultWidth = 40
+
+// Width is the width of the view.
+//
+// Deprecated: use DefaultWidth.
+const Width = DefaultWidth
+
+const (
+	minListWidth   = 20 // the listing beside the preview is never narrower
+	minFilenameSz  = 12 // below it, the columns are hidden
+	wideFilenameSz = 32 // optional columns are shown, if name stays wider
+)
+
+// layout is the layout of the listing row.
+type layout struct {
+	name    int // width of the name column
+	columns []Column
+	widths  []int
+}
+
+// width returns the width of the view.
+func (m Model) width() int {
+	switch {
+	case m.Width > 0:
+		return m.Width
+	case m.winWidth > 0:
+		return m.winWidth
+	}
+	return DefaultWidth
+}
+
+// listWidth returns the width of the listing, that shares the view with
+// the right preview pane.
+func (m Model) listWidth() int {
+	w := m.width()
+	if m.previewVisible() && m.Preview == PreviewRight {
+		w = max(w-m.previewWidth(), minListWidth)
+	}
+	return w
+}
+
+// layout returns the row layout for the listing width.  If the name column
+// gets too narrow, the lowest priority columns are hidden, while there is
+// plenty of room, the optional columns are added and the columns are
+// widened.
+func (m Model) layout() layout {
+	var (
+		avail = m.listWidth() - 1
+		all   = m.columns()
+		shown = make([]bool, len(all))
+		l     = layout{widths: make([]int, len(all))}
+	)
+	nameWidth := func() int {
+		w := avail
+		for i := range all {
+			if shown[i] {
+				w -= l.widths[i] + 1
+			}
+		}
+		return w
+	}
+	for i, c := range all {
+		shown[i] = !c.Width().Optional
+		l.widths[i] = c.Width().Min
+	}
+	for nameWidth() < minFilenameSz {
+		hide := -1
+		for i, c := range all {
+			if shown[i] && (hide < 0 || c.Width().Priority <= all[hide].Width().Priority) {
+				hide = i
+			}
+		}
+		if hide < 0 {
+			break
+		}
+		shown[hide] = false
+	}
+	for i, c := range all {
+		if c.Width().Optional {
+			shown[i] = true
+			if nameWidth() < wideFilenameSz {
+				shown[i] = false
+			}
+		}
+	}
+	for i, c := range all {
+		if shown[i] && c.Width().Max > l.widths[i] {
+			l.widths[i] = c.Width().Max
+			if nameWidth() < wideFilenameSz {
+				l.widths[i] = c.Width().Min
+			}
+		}
+	}
+	l.name = max(nameWidth(), 1)
+	for i, c := range all {
+		if shown[i] {
+			l.columns = append(l.columns, c)
+			l.widths[len(l.columns)-1] = l.widths[i]
+		}
+	}
+	l.widths = l.widths[:len(l.columns)]
+	return l
+}
+
+// printFile returns the listing row for the file.
+func (l layout) printFile(fi fs.FileInfo) string {
+	var buf strings.Builder
+	buf.WriteString(display.Fit(displayName(fi), l.name))
+	for i, c := range l.columns {
+		buf.WriteString(" " + display.Fit(c.Render(fi, l.widths[i]), l.widths[i]))
+	}
+	return buf.String()
+}
+
+// printHeader returns the header row.
+func (l layout) printHeader() string {
+	var buf strings.Builder
+	buf.WriteString(display.Fit("Name", l.name))
+	for i, c := range l.columns {
+		buf.WriteString(" " + display.Fit(c.Header(), l.widths[i]))
+	}
+	return buf.String()
+}
@@ -0,0 +1,126 @@
+package filemgr
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_layout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"file.txt": &fstest.MapFile{
+			Data:    []byte("hello"),
+			Mode:    0o644,
+			ModTime: time.Date(2024, 3, 15, 10, 20, 30, 0, time.UTC),
+		},
+	}
+	fi := must(fs.Stat(fsys, "file.txt"))
+	tests := []struct {
+		name  string
+		model Model
+		want  string
+	}{
+		{
+			name:  "default width",
+			model: Model{},
+			want:  "file.txt            5B 15-03-2024 10:20",
+		},
+		{
+			name:  "wide",
+			model: Model{Width: 50},
+			want:  "file.txt                      5B 15-03-2024 10:20",
+		},
+		{
+			name:  "time is hidden",
+			model: Model{Width: 30},
+			want:  "file.txt                   5B",
+		},
+		{
+			name:  "only name",
+			model: Model{Width: 16},
+			want:  "file.txt       ",
+		},
+		{
+			name:  "no room for optional columns",
+			model: Model{ShowPerms: true, ShowFullTime: true, ShowOwner: true},
+			want:  "file.txt            5B 15-03-2024 10:20",
+		},
+		{
+			name:  "optional columns",
+			model: Model{Width: 80, ShowPerms: true, ShowFullTime: true},
+			want:  "file.txt                                      5B -rw-r--r-- 2024-03-15 10:20:30",
+		},
+		{
+			name:  "date column hidden",
+			model: Model{Columns: []Column{SizeColumn}},
+			want:  "file.txt                             5B",
+		},
+		{
+			name: "custom column",
+			model: Model{Columns: []Column{
+				NewColumn("Msgs", ColumnWidth{Min: 4, Priority: 3}, func(fi fs.FileInfo, width int) string {
+					return fmt.Sprintf("%*d", width, 42)
+				}),
+				TimeColumn,
+			}},
+			want: "file.txt            42 15-03-2024 10:20",
+		},
+		{
+			name:  "lowest priority column is hidden first",
+			model: Model{Width: 30, Columns: []Column{TimeColumn, SizeColumn}},
+			want:  "file.txt                   5B",
+		},
+		{
+			name:  "listing beside the preview",
+			model: Model{Width: 74, Preview: PreviewRight, PreviewWidth: 30},
+			want:  "file.txt                5B 15-03-2024 10:20",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.model.layout().printFile(fi)
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, got, tt.model.listWidth()-1)
+		})
+	}
+}
+
+func TestModel_layoutWide(t *testing.T) {
+	fsys := fstest.MapFS{
+		"日本語のファイル名.txt": &fstest.MapFile{Data: []byte("hello")},
+		"日本.txt":        &fstest.MapFile{Data: []byte("hello")},
+	}
+	m := Model{}
+	for _, name := range []string{"日本語のファイル名.txt", "日本.txt"} {
+		got := m.layout().printFile(must(fs.Stat(fsys, name)))
+		assert.Equal(t, m.listWidth()-1, lipgloss.Width(got), name)
+		assert.True(t, strings.HasSuffix(got, " 5B 01-01-0001 00:00"), "columns are aligned: %q", got)
+	}
+}
+
+func TestModel_printHeader(t *testing.T) {
+	m := New(testfs, ".", 5, "*")
+	m.ShowHeader = true
+	m.Select("")
+	lines := strings.Split(m.View(), "\n")
+	assert.Equal(t, "Name            Size   Modified        ", lines[0])
+	assert.Equal(t, 1+m.height(), len(lines)-1)
+}
+
+func TestModel_WindowSize(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	assert.Equal(t, 60, m.listWidth())
+	assert.Equal(t, 10, m.Height)
+
+	// explicit width takes precedence
+	m.Width = 50
+	assert.Equal(t, 50, m.listWidth())
+}
@@ -0,0 +1,272 @@
+package filemgr
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	firstChunkSz = 256      // entries in the first chunk, shown immediately
+	maxChunkSz   = 16 << 10 // chunk size doubles up to this value
+)
+
+var (
+	lastModelID atomic.Uint64 // model instance ids
+	lastLoadID  atomic.Uint64 // directory load ids, increasing
+)
+
+// wmReadDir is sent for each chunk of the directory being read.
+type wmReadDir struct {
+	model  uint64 // model instance id
+	id     uint64 // load id
+	dir    string
+	first  bool // first chunk of the load
+	files  []fs.FileInfo
+	ignore ignoreRules // set on the first chunk
+	ld     *loader     // nil if the load is finished
+}
+
+// wmLoadError is sent when the directory can't be read.
+type wmLoadError struct {
+	model uint64
+	id    uint64
+	dir   string
+	err   error
+}
+
+// loader reads the directory in chunks.  It is owned by the command that
+// reads the next chunk, and is never accessed concurrently.
+type loader struct {
+	fsys fs.FS
+	f    fs.ReadDirFile
+	dir  string
+	mt   *matcher
+	sz   int // next chunk size
+}
+
+// load returns the command that starts reading the current directory.
+func (m Model) load() tea.Cmd {
+	var (
+		model = m.id
+		id    = lastLoadID.Add(1)
+		fsys  = m.FS
+		dir   = m.Directory
+		names = m.IgnoreFiles
+	)
+	mt, err := m.matcher()
+	return func() tea.Msg {
+		if err != nil {
+			return wmLoadError{model: model, id: id, dir: dir, err: err}
+		}
+		ignore, err := loadIgnore(fsys, dir, names...)
+		if err != nil {
+			return wmLoadError{model: model, id: id, dir: dir, err: err}
+		}
+		ld, err := openLoader(fsys, dir, mt)
+		if err != nil {
+			return wmLoadError{model: model, id: id, dir: dir, err: err}
+		}
+		files, err := ld.next()
+		if err != nil {
+			ld.close()
+			return wmLoadError{model: model, id: id, dir: dir, err: err}
+		}
+		if !isRoot(dir) {
+			files = append([]fs.FileInfo{specialDir{".."}}, files...)
+		}
+		msg := wmReadDir{model: model, id: id, dir: dir, first: true, files: files, ignore: ignore}
+		if !ld.done() {
+			msg.ld = ld
+		}
+		return msg
+	}
+}
+
+// loadDir returns the command that starts reading the current directory,
+// that has just changed.  The listing of the previous directory is cleared,
+// so that "Loading…" is shown and nothing is acted on until the new one
+// arrives.
+func (m *Model) loadDir() tea.Cmd {
+	m.files, m.entries = nil, nil
+	m.err = nil
+	m.loading = true
+	return m.load()
+}
+
+// nextCmd returns the command that reads the next chunk of the load.
+func (msg wmReadDir) nextCmd() tea.Cmd {
+	if msg.ld == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		next := wmReadDir{model: msg.model, id: msg.id, dir: msg.dir}
+		files, err := msg.ld.next()
+		if err != nil {
+			msg.ld.close()
+			return wmLoadError{model: msg.model, id: msg.id, dir: msg.dir, err: err}
+		}
+		next.files = files
+		if !msg.ld.done() {
+			next.ld = msg.ld
+		}
+		return next
+	}
+}
+
+// cancelCmd returns the command that stops the load.
+func (msg wmReadDir) cancelCmd() tea.Cmd {
+	if msg.ld == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg.ld.close()
+		return nil
+	}
+}
+
+func isRoot(dir string) bool {
+	return dir == "." || dir == "/" || dir == ""
+}
+
+// fsDir returns the slash-separated fs.FS path of the directory.
+func fsDir(dir string) string {
+	if isRoot(dir) {
+		return "."
+	}
+	return path.Clean(filepath.ToSlash(dir))
+}
+
+func openLoader(fsys fs.FS, dir string, mt *matcher) (*loader, error) {
+	f, err := fsys.Open(fsDir(dir))
+	if err != nil {
+		return nil, err
+	}
+	rdf, ok := f.(fs.ReadDirFile)
+	if !ok {
+		f.Close()
+		return nil, &fs.PathError{Op: "readdir", Path: dir, Err: errors.New("not implemented")}
+	}
+	return &loader{fsys: fsys, f: rdf, dir: dir, mt: mt, sz: firstChunkSz}, nil
+}
+
+// next reads the next chunk of entries, that are directories or match
+// the globs.
+func (l *loader) next() ([]fs.FileInfo, error) {
+	entries, err := l.f.ReadDir(l.sz)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err == io.EOF || len(entries) < l.sz {
+		l.close()
+	}
+	l.sz = min(l.sz*2, maxChunkSz)
+	files := make([]fs.FileInfo, 0, len(entries))
+	for _, de := range entries {
+		name := path.Join(fsDir(l.dir), de.Name())
+		if de.Type()&fs.ModeSymlink != 0 {
+			// links are matched as their destinations.
+			if fi, err := de.Info(); err == nil {
+				de = fs.FileInfoToDirEntry(resolveLink(l.fsys, name, fi))
+			}
+		}
+		// each entry is matched once, so the file matching several
+		// patterns is listed once.
+		if !l.mt.match(name, de) {
+			continue
+		}
+		fi, err := de.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // removed while reading
+			}
+			return nil, err
+		}
+		files = append(files, fi)
+	}
+	return files, nil
+}
+
+func (l *loader) done() bool {
+	return l.f == nil
+}
+
+func (l *loader) close() {
+	if l.f != nil {
+		l.f.Close()
+		l.f = nil
+	}
+}
+
+// readDir reads the whole directory synchronously.
+func readDir(fsys fs.FS, dir string, mt *matcher) ([]fs.FileInfo, error) {
+	files, err := readEntries(fsys, dir, mt)
+	if err != nil {
+		return nil, err
+	}
+	if !isRoot(dir) {
+		files = append([]fs.FileInfo{specialDir{".."}}, files...)
+	}
+	return files, nil
+}
+
+// readEntries reads the whole directory synchronously, without the ".."
+// entry.
+func readEntries(fsys fs.FS, dir string, mt *matcher) ([]fs.FileInfo, error) {
+	ld, err := openLoader(fsys, dir, mt)
+	if err != nil {
+		return nil, err
+	}
+	defer ld.close()
+	var files []fs.FileInfo
+	for !ld.done() {
+		chunk, err := ld.next()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, chunk...)
+	}
+	return files, nil
+}
+
+// loaded processes the chunk of the directory listing.
+func (m *Model) loaded(msg wmReadDir) tea.Cmd {
+	var cmd tea.Cmd // bookmarks saving
+	switch {
+	case msg.first && msg.dir == m.Directory && msg.id > m.loadID:
+		m.keep, m.reveal = m.reveal, ""
+		if m.listed == msg.dir {
+			m.keep = m.current() // refresh, keep the cursor on the same file
+		}
+		m.loadID = msg.id
+		m.err = nil
+		m.ignore = msg.ignore
+		m.populate(msg.files)
+		if m.listed != msg.dir {
+			m.visit(msg.dir)
+			cmd = m.saveBookmarksCmd()
+		}
+		m.listed = msg.dir
+	case msg.id == m.loadID && msg.dir == m.Directory:
+		if m.keep == "" {
+			m.keep = m.current()
+		}
+		m.populate(append(m.entries, msg.files...))
+	default:
+		return msg.cancelCmd() // stale
+	}
+	if m.keep != "" {
+		m.focusName(m.keep)
+	}
+	m.loading = msg.ld != nil
+	if !m.loading {
+		m.keep = ""
+		return tea.Batch(cmd, m.watchCmd(), m.refreshTreeCmd())
+	}
+	return tea.Batch(cmd, msg.nextCmd())
+}
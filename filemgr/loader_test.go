@@ -0,0 +1,91 @@
+package filemgr
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func bigFS(n int) fstest.MapFS {
+	fsys := fstest.MapFS{"dir/file.txt": &fstest.MapFile{}}
+	for i := range n {
+		fsys[fmt.Sprintf("file%05d.txt", i)] = &fstest.MapFile{}
+	}
+	return fsys
+}
+
+func TestModel_load(t *testing.T) {
+	const n = 1000
+	m := New(bigFS(n), ".", 10, "*")
+	m.Focus()
+
+	msg, ok := m.load()().(wmReadDir)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, msg.first)
+	assert.Len(t, msg.files, firstChunkSz)
+	assert.NotNil(t, msg.ld, "load is not finished")
+
+	m, cmd := m.Update(msg)
+	assert.True(t, m.loading)
+	assert.Len(t, m.files, firstChunkSz)
+	assert.Contains(t, m.View(), "Loading…")
+
+	m = run(m, cmd)
+	assert.False(t, m.loading)
+	assert.Len(t, m.files, n+1)
+	assert.NotContains(t, m.View(), "Loading…")
+}
+
+func TestModel_loadStale(t *testing.T) {
+	m := New(bigFS(1000), ".", 10, "*")
+	m.Focus()
+
+	m, cmd := m.Update(m.load()())
+	next := cmd
+	// navigate into "dir" while the root is still loading.
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = run(m, cmd)
+	assert.Equal(t, "dir", m.Directory)
+	assert.Len(t, m.files, 2) // ".." and file.txt
+
+	// chunk of the old load is dropped
+	stale := collectMsgs(next)
+	if assert.Len(t, stale, 1) {
+		m, cmd = m.Update(stale[0])
+		assert.Len(t, m.files, 2)
+		assert.Equal(t, []tea.Msg{nil}, collectMsgs(cmd), "should cancel the load")
+		assert.True(t, stale[0].(wmReadDir).ld.done())
+	}
+}
+
+func TestModel_loadOtherModel(t *testing.T) {
+	m1 := New(testfs, ".", 10, "*")
+	m2 := New(testfs, ".", 10, "*.bin")
+	m2, _ = m2.Update(m1.Init()())
+	assert.Empty(t, m2.files, "should ignore the listing of another model")
+}
+
+func TestModel_loadClears(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.Focus()
+	m = run(m, m.load())
+	m.Select("dir2")
+
+	// the new directory is loading, the old listing is gone
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "dir2", m.Directory)
+	assert.True(t, m.loading)
+	assert.Empty(t, m.files)
+	assert.Contains(t, m.View(), "Loading…")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "dir2", m.Directory, "nothing to enter while loading")
+
+	m = run(m, cmd)
+	assert.False(t, m.loading)
+	assert.NotEmpty(t, m.files)
+}
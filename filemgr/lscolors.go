@@ -0,0 +1,128 @@
+package filemgr
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LSColors returns the copy of the style with the colours from the
+// LS_COLORS specification applied, i.e.:
+//
+//	m.Style = m.Style.LSColors(os.Getenv("LS_COLORS"))
+//
+// The file type keys di, ex, ln, or, pi, so, bd, cd, fi and the "*.ext"
+// patterns are used, the rest and the malformed entries are ignored.
+func (s Style) LSColors(spec string) Style {
+	s.Ext = maps.Clone(s.Ext)
+	if s.Ext == nil {
+		s.Ext = make(map[string]lipgloss.Style)
+	}
+	for _, entry := range strings.Split(spec, ":") {
+		key, codes, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		st, err := sgrStyle(codes)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "di":
+			s.Directory = st
+		case "ex":
+			s.Executable = st
+		case "ln":
+			s.Symlink = st
+		case "or":
+			s.Broken = st
+		case "pi", "so", "bd", "cd":
+			s.Special = st
+		case "fi":
+			s.Normal = st
+		default:
+			if ext, ok := strings.CutPrefix(key, "*."); ok && ext != "" {
+				s.Ext["."+strings.ToLower(ext)] = st
+			}
+		}
+	}
+	return s
+}
+
+// sgrStyle returns the style for the semicolon-separated SGR codes, as
+// used in LS_COLORS.
+func sgrStyle(codes string) (lipgloss.Style, error) {
+	st := lipgloss.NewStyle()
+	params := strings.Split(codes, ";")
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			return st, err
+		}
+		switch {
+		case n == 0:
+			st = lipgloss.NewStyle()
+		case n == 1:
+			st = st.Bold(true)
+		case n == 2:
+			st = st.Faint(true)
+		case n == 3:
+			st = st.Italic(true)
+		case n == 4:
+			st = st.Underline(true)
+		case n == 5:
+			st = st.Blink(true)
+		case n == 7:
+			st = st.Reverse(true)
+		case n == 9:
+			st = st.Strikethrough(true)
+		case 30 <= n && n <= 37:
+			st = st.Foreground(lipgloss.Color(strconv.Itoa(n - 30)))
+		case 40 <= n && n <= 47:
+			st = st.Background(lipgloss.Color(strconv.Itoa(n - 40)))
+		case 90 <= n && n <= 97:
+			st = st.Foreground(lipgloss.Color(strconv.Itoa(n - 90 + 8)))
+		case 100 <= n && n <= 107:
+			st = st.Background(lipgloss.Color(strconv.Itoa(n - 100 + 8)))
+		case n == 38 || n == 48:
+			c, used, err := sgrColor(params[i+1:])
+			if err != nil {
+				return st, err
+			}
+			i += used
+			if n == 38 {
+				st = st.Foreground(c)
+			} else {
+				st = st.Background(c)
+			}
+		}
+	}
+	return st, nil
+}
+
+// sgrColor parses the extended colour, that follows the 38 or 48 code:
+// "5;n" for the 256 colour palette, or "2;r;g;b" for the true colour.  It
+// returns the number of parameters used.
+func sgrColor(params []string) (lipgloss.Color, int, error) {
+	var nums []int
+	for _, p := range params {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			return "", 0, fmt.Errorf("invalid colour parameter: %q", p)
+		}
+		nums = append(nums, n)
+		if (nums[0] == 5 && len(nums) == 2) || (nums[0] == 2 && len(nums) == 4) {
+			break
+		}
+	}
+	switch {
+	case len(nums) == 2 && nums[0] == 5:
+		return lipgloss.Color(strconv.Itoa(nums[1])), 2, nil
+	case len(nums) == 4 && nums[0] == 2:
+		return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", nums[1], nums[2], nums[3])), 4, nil
+	}
+	return "", 0, fmt.Errorf("invalid extended colour: %v", params)
+}
@@ -0,0 +1,124 @@
+package filemgr
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mapFile is the file of mapFS.
+type mapFile struct {
+	Data    []byte
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// mapFS is the in-memory file system of the slash-separated paths to the
+// files.  Parent directories missing in the map are implied.  It does what
+// fstest.MapFS does for the package, without linking the testing package
+// into the binaries.
+type mapFS map[string]*mapFile
+
+func (m mapFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	f, ok := m[name]
+	if ok && !f.Mode.IsDir() {
+		return &openMapFile{Reader: bytes.NewReader(f.Data), info: mapFileInfo{path.Base(name), f}}, nil
+	}
+	entries := m.readDir(name)
+	if !ok {
+		if name != "." && len(entries) == 0 {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		f = impliedDir
+	}
+	return &mapDir{info: mapFileInfo{path.Base(name), f}, entries: entries}, nil
+}
+
+// impliedDir is the directory, that is not in the map, but has files.
+var impliedDir = &mapFile{Mode: fs.ModeDir | 0o555}
+
+// readDir returns the sorted entries of the directory.
+func (m mapFS) readDir(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	var entries []fs.DirEntry
+	for name := range m {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		elem, _, _ := strings.Cut(rest, "/")
+		if seen[elem] {
+			continue
+		}
+		seen[elem] = true
+		f, ok := m[prefix+elem]
+		if !ok {
+			f = impliedDir
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(mapFileInfo{elem, f}))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries
+}
+
+type mapFileInfo struct {
+	name string
+	f    *mapFile
+}
+
+func (i mapFileInfo) Name() string       { return i.name }
+func (i mapFileInfo) Size() int64        { return int64(len(i.f.Data)) }
+func (i mapFileInfo) Mode() fs.FileMode  { return i.f.Mode }
+func (i mapFileInfo) ModTime() time.Time { return i.f.ModTime }
+func (i mapFileInfo) IsDir() bool        { return i.f.Mode.IsDir() }
+func (i mapFileInfo) Sys() any           { return nil }
+
+// openMapFile is the open regular file.
+type openMapFile struct {
+	*bytes.Reader
+	info mapFileInfo
+}
+
+func (f *openMapFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openMapFile) Close() error               { return nil }
+
+// mapDir is the open directory.
+type mapDir struct {
+	info    mapFileInfo
+	entries []fs.DirEntry
+	off     int
+}
+
+func (d *mapDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *mapDir) Close() error               { return nil }
+
+func (d *mapDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *mapDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.off:]
+	if n <= 0 {
+		d.off = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.off += len(rest)
+	return rest, nil
+}
@@ -0,0 +1,115 @@
+package filemgr
+
+import (
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateMarks processes the multi-select keys.  It returns false if the key
+// is not a multi-select key.
+func (m *Model) updateMarks(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.Keys.Mark):
+		if len(m.files) == 0 {
+			break
+		}
+		m.toggleMark(m.files[m.st.Cursor])
+		m.st.Down(len(m.files))
+	case key.Matches(msg, m.Keys.MarkAll):
+		for _, fi := range m.files {
+			m.setMark(fi, true)
+		}
+	case key.Matches(msg, m.Keys.InvertMark):
+		for _, fi := range m.files {
+			m.toggleMark(fi)
+		}
+	case key.Matches(msg, m.Keys.MarkGlob):
+		return m.openPrompt(promptMark, "Mark: ", "*"), true
+	case key.Matches(msg, m.Keys.UnmarkGlob):
+		return m.openPrompt(promptUnmark, "Unmark: ", "*"), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// markable returns true if the file can be marked.
+func markable(fi fs.FileInfo) bool {
+	_, special := fi.(specialDir)
+	return !special
+}
+
+func (m Model) path(fi fs.FileInfo) string {
+	return filepath.Join(m.Directory, fi.Name())
+}
+
+func (m Model) isMarked(fi fs.FileInfo) bool {
+	_, ok := m.marked[m.path(fi)]
+	return ok
+}
+
+func (m *Model) setMark(fi fs.FileInfo, mark bool) {
+	if !markable(fi) {
+		return
+	}
+	if !mark {
+		delete(m.marked, m.path(fi))
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]struct{})
+	}
+	m.marked[m.path(fi)] = struct{}{}
+}
+
+func (m *Model) toggleMark(fi fs.FileInfo) {
+	m.setMark(fi, !m.isMarked(fi))
+}
+
+// markGlob marks or unmarks all files in the current listing that match the
+// glob.
+func (m *Model) markGlob(glob string, mark bool) {
+	for _, fi := range m.files {
+		ok, err := filepath.Match(glob, filepath.Base(fi.Name()))
+		if err != nil {
+			slog.Error("markGlob", "glob", glob, "err", err)
+			return
+		}
+		if ok {
+			m.setMark(fi, mark)
+		}
+	}
+}
+
+// Marked returns the sorted list of marked file paths.
+func (m Model) Marked() []string {
+	paths := make([]string, 0, len(m.marked))
+	for p := range m.marked {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// ClearMarks unmarks all files.
+func (m *Model) ClearMarks() {
+	clear(m.marked)
+}
+
+// multiSelectedCmd returns the command that sends the marked files, or the
+// file under cursor, if nothing is marked.
+func (m Model) multiSelectedCmd() tea.Cmd {
+	paths := m.Marked()
+	if len(paths) == 0 {
+		paths = []string{m.path(m.files[m.st.Cursor])}
+	}
+	filter := m.filterName()
+	return func() tea.Msg {
+		return WMMultiSelected{Filepaths: paths, Filter: filter}
+	}
+}
@@ -0,0 +1,82 @@
+package filemgr
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_markGlob(t *testing.T) {
+	tests := []struct {
+		name  string
+		globs []string
+		want  []string
+	}{
+		{
+			name:  "mark text files",
+			globs: []string{"*.txt"},
+			want:  []string{"file1.txt", "file2.txt", "file3.txt"},
+		},
+		{
+			name:  "mark nothing",
+			globs: []string{"*.foo"},
+			want:  []string{},
+		},
+		{
+			name:  "marking twice does not duplicate",
+			globs: []string{"*.bin", "binary1.*"},
+			want:  []string{"binary1.bin", "binary2.bin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(testfs, ".", 10, "*")
+			m.Select("") // populate the listing
+			for _, glob := range tt.globs {
+				m.markGlob(glob, true)
+			}
+			assert.Equal(t, tt.want, m.Marked())
+		})
+	}
+}
+
+func TestModel_updateMarks(t *testing.T) {
+	m := New(testfs, "dir2", 10, "*")
+	m.MultiSelect = true
+	m.Focus()
+	m.Select("") // populate the listing
+
+	// ".." can't be marked
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Empty(t, m.Marked())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, []string{"dir2/dirfile.txt"}, m.Marked())
+
+	// invert
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	assert.Empty(t, m.Marked())
+
+	// select all, then confirm
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []tea.Msg{WMMultiSelected{Filepaths: []string{"dir2/dirfile.txt"}}}, collectMsgs(cmd))
+}
+
+// collectMsgs runs the command and returns all messages it produces,
+// unwrapping batches.
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, collectMsgs(c)...)
+	}
+	return msgs
+}
@@ -0,0 +1,100 @@
+package filemgr
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// matcher decides which files are listed.  Directories are listed
+// regardless of the patterns and the regular expression.
+type matcher struct {
+	include  []string // patterns of the files to list
+	exclude  []string // negated patterns
+	fold     bool     // case-insensitive
+	re       *regexp.Regexp
+	fn       func(name string, de fs.DirEntry) bool
+	dirsOnly bool // nothing to match the files with
+}
+
+// newMatcher returns the matcher for the glob patterns, the regular
+// expression expr, if not empty, and the predicate fn, if not nil.
+// Patterns prefixed with "!" exclude the matching files, patterns
+// containing "/" are matched against the path relative to the root of the
+// file system, "**" matches any number of directories.  Without the
+// patterns, the expression and the predicate, only directories match.
+func newMatcher(globs []string, ignoreCase bool, expr string, fn func(string, fs.DirEntry) bool) (*matcher, error) {
+	mt := &matcher{fold: ignoreCase, fn: fn, dirsOnly: len(globs) == 0 && expr == "" && fn == nil}
+	for _, glob := range globs {
+		if ignoreCase {
+			glob = strings.ToLower(glob)
+		}
+		negate := strings.HasPrefix(glob, "!")
+		glob = strings.TrimPrefix(glob, "!")
+		for _, seg := range strings.Split(glob, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, err
+			}
+		}
+		if negate {
+			mt.exclude = append(mt.exclude, glob)
+		} else {
+			mt.include = append(mt.include, glob)
+		}
+	}
+	if expr != "" {
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		mt.re = re
+	}
+	return mt, nil
+}
+
+// match returns true if the entry should be listed.  name is the path of
+// the entry relative to the root of the file system.
+func (mt *matcher) match(name string, de fs.DirEntry) bool {
+	if !de.IsDir() {
+		if mt.dirsOnly {
+			return false
+		}
+		target := name
+		if mt.fold {
+			target = strings.ToLower(name)
+		}
+		if len(mt.include) > 0 && !matchGlobs(mt.include, target) {
+			return false
+		}
+		if matchGlobs(mt.exclude, target) {
+			return false
+		}
+		if mt.re != nil && !mt.re.MatchString(de.Name()) {
+			return false
+		}
+	}
+	return mt.fn == nil || mt.fn(name, de)
+}
+
+// matchGlobs returns true if the name matches any of the globs.
+func matchGlobs(globs []string, name string) bool {
+	for _, glob := range globs {
+		if strings.Contains(glob, "/") {
+			if matchPath(glob, name) {
+				return true
+			}
+		} else if ok, _ := path.Match(glob, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matcher returns the matcher for the filter settings of the model.
+func (m Model) matcher() (*matcher, error) {
+	return newMatcher(m.globs(), m.IgnoreCase, m.Regexp, m.MatchFunc)
+}
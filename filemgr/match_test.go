@@ -0,0 +1,120 @@
+package filemgr
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/b/data.json":   &fstest.MapFile{},
+		"a/b/DATA.JSON":   &fstest.MapFile{},
+		"a/b/export.zip":  &fstest.MapFile{},
+		"a/b/skip.json":   &fstest.MapFile{},
+		"a/b/sub/x.json":  &fstest.MapFile{},
+		"a/b/notes.txt":   &fstest.MapFile{},
+		"a/b/channels.db": &fstest.MapFile{},
+	}
+	tests := []struct {
+		name       string
+		globs      []string
+		ignoreCase bool
+		expr       string
+		fn         func(string, fs.DirEntry) bool
+		want       []string
+	}{
+		{
+			name:  "base name",
+			globs: []string{"*.json"},
+			want:  []string{"data.json", "skip.json", "sub"},
+		},
+		{
+			name:  "double star",
+			globs: []string{"**/b/*.zip"},
+			want:  []string{"export.zip", "sub"},
+		},
+		{
+			name:  "relative path must match entirely",
+			globs: []string{"b/*.zip"},
+			want:  []string{"sub"},
+		},
+		{
+			name:  "negation",
+			globs: []string{"*.json", "!skip.*"},
+			want:  []string{"data.json", "sub"},
+		},
+		{
+			name:  "negation only",
+			globs: []string{"!*.json", "!*.JSON"},
+			want:  []string{"channels.db", "export.zip", "notes.txt", "sub"},
+		},
+		{
+			name:       "case-insensitive",
+			globs:      []string{"data.*"},
+			ignoreCase: true,
+			want:       []string{"DATA.JSON", "data.json", "sub"},
+		},
+		{
+			name: "no globs",
+			want: []string{"sub"},
+		},
+		{
+			name: "regexp",
+			expr: `^(data|notes)\.`,
+			want: []string{"data.json", "notes.txt", "sub"},
+		},
+		{
+			name:       "regexp case-insensitive",
+			expr:       `^data\.json$`,
+			ignoreCase: true,
+			want:       []string{"DATA.JSON", "data.json", "sub"},
+		},
+		{
+			name:  "predicate",
+			globs: []string{"*"},
+			fn: func(name string, de fs.DirEntry) bool {
+				return !de.IsDir() && strings.HasPrefix(name, "a/b/c")
+			},
+			want: []string{"channels.db"},
+		},
+		{
+			name:  "no duplicates",
+			globs: []string{"*.json", "data.*", "**/*.json"},
+			want:  []string{"data.json", "skip.json", "sub"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt, err := newMatcher(tt.globs, tt.ignoreCase, tt.expr, tt.fn)
+			require.NoError(t, err)
+			files, err := readDir(fsys, "a/b", mt)
+			require.NoError(t, err)
+			var got []string
+			for _, fi := range files {
+				if markable(fi) {
+					got = append(got, fi.Name())
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_newMatcher_errors(t *testing.T) {
+	_, err := newMatcher([]string{"**/[a"}, false, "", nil)
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+	_, err = newMatcher(nil, false, "(", nil)
+	assert.Error(t, err)
+
+	m := New(testfs, ".", 10, "*")
+	m.Regexp = "("
+	msg, ok := m.load()().(wmLoadError)
+	assert.True(t, ok)
+	assert.Error(t, msg.err)
+}
@@ -0,0 +1,173 @@
+package filemgr
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// doubleClick is the maximum interval between the clicks of a double-click.
+const doubleClick = 400 * time.Millisecond
+
+// debugLines is the number of lines printed by printDebug.
+const debugLines = 7
+
+// click is the last click on the listing.
+type click struct {
+	at  time.Time
+	idx int
+}
+
+// listTop returns the row of the first line of the listing, relative to the
+// top of the view.
+func (m Model) listTop() int {
+	var top int
+	if m.Debug {
+		top += debugLines
+	}
+	if m.ShowPath {
+		top++
+	}
+	if m.ShowHeader {
+		top++
+	}
+	return top
+}
+
+// helpRow returns the row of the help line, relative to the top of the
+// view.
+func (m Model) helpRow() int {
+	row := m.filterRow()
+	if len(m.Filters) > 0 {
+		row++
+	}
+	if m.SaveAs {
+		row++
+	}
+	if m.opErr != nil {
+		row++
+	}
+	if m.confirm.active() || m.prompt != promptNone {
+		row++
+	}
+	if m.gotoErr != nil {
+		row++
+	}
+	if len(m.completions) > 0 {
+		row++
+	}
+	return row + 1 // empty line
+}
+
+// filterRow returns the row of the named filter line, that follows the
+// listing, relative to the top of the view.
+func (m Model) filterRow() int {
+	row := m.listTop() + m.height()
+	if m.ShowHeader && m.places {
+		row-- // the popup has no header
+	}
+	if m.previewVisible() && m.Preview == PreviewBottom {
+		row += m.previewHeight()
+	}
+	if m.loading && len(m.files) > 0 {
+		row++
+	}
+	return row
+}
+
+// updateMouse processes the mouse message.
+func (m Model) updateMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if !m.focus || m.places || m.crumbs || m.prompt != promptNone || m.confirm.active() {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.st.Up()
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.st.Down(len(m.files))
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+	x, y := msg.X-m.Left, msg.Y-m.Top
+	if m.ShowHelp && y == m.helpRow() {
+		return m.clickHelp(x)
+	}
+	if len(m.Filters) > 0 && y == m.filterRow() {
+		return m, m.nextFilter()
+	}
+	row := y - m.listTop()
+	if x < 0 || x >= m.listWidth() || row < 0 || row >= m.height() {
+		return m, nil
+	}
+	idx := m.st.Min + row
+	if idx >= len(m.files) || idx > m.st.Max {
+		return m, nil
+	}
+	now := time.Now()
+	double := idx == m.lastClick.idx && now.Sub(m.lastClick.at) <= doubleClick
+	m.st.Cursor = idx
+	if double {
+		m.lastClick = click{}
+		return m.activate()
+	}
+	m.lastClick = click{at: now, idx: idx}
+	return m, nil
+}
+
+// clickHelp sends the key of the short help item at column x.
+func (m Model) clickHelp(x int) (Model, tea.Cmd) {
+	if m.help.ShowAll {
+		return m, nil
+	}
+	col := helpIndent
+	for _, b := range m.activeKeys().ShortHelp() {
+		if !b.Enabled() {
+			continue
+		}
+		if col > helpIndent {
+			col += lipgloss.Width(m.help.ShortSeparator)
+		}
+		w := lipgloss.Width(b.Help().Key + " " + b.Help().Desc)
+		if col <= x && x < col+w {
+			return m.update(keyMsg(b.Keys()[0]))
+		}
+		col += w
+	}
+	return m, nil
+}
+
+// keyTypes maps the key names, as returned by tea.KeyMsg.String, to the
+// key types: the special keys are negative, the control keys are from 0 to
+// 127 (backspace).
+var keyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for t := tea.KeyF20; t <= tea.KeyBackspace; t++ {
+		if name := t.String(); t != tea.KeyRunes && name != "" {
+			types[name] = t
+		}
+	}
+	return types
+}()
+
+// keyMsg returns the key message for the key name, as returned by
+// tea.KeyMsg.String.
+func keyMsg(name string) tea.KeyMsg {
+	var k tea.Key
+	if s, ok := strings.CutPrefix(name, "alt+"); ok && s != "" {
+		k.Alt, name = true, s
+	}
+	if t, ok := keyTypes[name]; ok {
+		k.Type = t
+		return tea.KeyMsg(k)
+	}
+	k.Type, k.Runes = tea.KeyRunes, []rune(name)
+	return tea.KeyMsg(k)
+}
@@ -0,0 +1,56 @@
+package filemgr
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_updateMouse(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.ShowHelp = true
+	m.ShowPath = true
+	m.Left, m.Top = 2, 3
+	m.Focus()
+	m = run(m, m.load())
+	press := func(x, y int) tea.Msg {
+		return tea.MouseMsg{X: m.Left + x, Y: m.Top + y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	}
+
+	// wheel moves the cursor
+	m, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown})
+	assert.Equal(t, "dir2", m.current())
+	m, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp})
+	assert.Equal(t, "dir1", m.current())
+
+	// single click moves the cursor, the path header is skipped
+	m, _ = m.Update(press(5, 3))
+	assert.Equal(t, "binary1.bin", m.current())
+	assert.Equal(t, ".", m.Directory)
+
+	// clicks outside of the listing are ignored
+	m, _ = m.Update(press(5, 0))
+	assert.Equal(t, "binary1.bin", m.current())
+
+	// double click enters the directory
+	m, _ = m.Update(press(5, 2))
+	m = run(m, func() tea.Msg { return press(5, 2) })
+	assert.Equal(t, "dir2", m.Directory)
+
+	// click on "⌫ back" in the help line
+	m = run(m, func() tea.Msg { return press(33, m.helpRow()) })
+	assert.Equal(t, ".", m.Directory)
+	assert.Equal(t, "dir2", m.current())
+}
+
+func TestModel_helpRow(t *testing.T) {
+	m := New(testfs, ".", 10, "*")
+	m.ShowHelp = true
+	m.SaveAs = true
+	m.Filters = []NamedFilter{{Name: "All", Globs: []string{"*"}}}
+	lines := strings.Count(m.View(), "\n")
+	// the help line is the last one, followed by the newline
+	assert.Equal(t, lines-1, m.helpRow())
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package filemgr
+
+import "io/fs"
+
+// owner returns the name of the file owner, which is not known on this
+// platform.
+func owner(fs.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,245 @@
+package filemgr
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/rusq/rbubbles/display"
+)
+
+// PreviewPosition is the position of the preview pane relative to the
+// listing.
+type PreviewPosition int
+
+const (
+	PreviewNone PreviewPosition = iota
+	PreviewRight
+	PreviewBottom
+)
+
+const (
+	defPreviewWidth  = 40
+	defPreviewHeight = 8
+
+	previewHeadSz = 4 << 10  // bytes read to preview a file
+	previewHexSz  = 256      // bytes shown in the hex dump
+	previewZipMax = 64 << 20 // max size of the zip file read into memory
+)
+
+var zipMagic = []byte("PK\x03\x04")
+
+// preview is the state of the preview pane.
+type preview struct {
+	path    string // file being previewed
+	content string
+	hidden  bool // preview is toggled off by the user
+}
+
+// wmPreview is sent when the preview of the file is loaded.
+type wmPreview struct {
+	path    string
+	content string
+}
+
+func (m Model) previewVisible() bool {
+	return m.Preview != PreviewNone && !m.pv.hidden
+}
+
+func (m Model) previewWidth() int {
+	if m.PreviewWidth > 0 {
+		return m.PreviewWidth
+	}
+	return defPreviewWidth
+}
+
+func (m Model) previewHeight() int {
+	if m.PreviewHeight > 0 {
+		return m.PreviewHeight
+	}
+	return defPreviewHeight
+}
+
+// previewLines returns the number of lines available for the preview.
+func (m Model) previewLines() int {
+	if m.Preview == PreviewBottom {
+		return m.previewHeight()
+	}
+	return m.height()
+}
+
+// updatePreview returns the command to load the preview of the file under
+// cursor, if it has changed.
+func (m *Model) updatePreview() tea.Cmd {
+	if !m.previewVisible() {
+		return nil
+	}
+	var fi fs.FileInfo
+	var p string
+	if m.st.Cursor < len(m.files) && markable(m.files[m.st.Cursor]) {
+		fi = m.files[m.st.Cursor]
+		p = m.path(fi)
+	}
+	if p == m.pv.path {
+		return nil
+	}
+	m.pv.path, m.pv.content = p, ""
+	if p == "" {
+		return nil
+	}
+	fsys, lines := m.FS, m.previewLines()
+	return func() tea.Msg {
+		return wmPreview{path: p, content: loadPreview(fsys, filepath.ToSlash(p), fi.IsDir(), lines)}
+	}
+}
+
+// loadPreview returns the preview of the file or directory at name.  Errors
+// are returned as the preview content.
+func loadPreview(fsys fs.FS, name string, isDir bool, lines int) string {
+	if isDir {
+		return previewDir(fsys, name, lines)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err.Error()
+	}
+	defer f.Close()
+	head := make([]byte, previewHeadSz)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err.Error()
+	}
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, zipMagic):
+		return previewZip(fsys, f, name, lines)
+	case isBinary(head):
+		return hex.Dump(head[:min(len(head), previewHexSz)])
+	default:
+		return previewText(head, lines)
+	}
+}
+
+func previewDir(fsys fs.FS, name string, lines int) string {
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		return err.Error()
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d entries\n", len(entries))
+	for i, de := range entries {
+		if i == lines-1 {
+			break
+		}
+		buf.WriteString(de.Name())
+		if de.IsDir() {
+			buf.WriteByte('/')
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func previewText(head []byte, lines int) string {
+	var buf strings.Builder
+	sc := bufio.NewScanner(bytes.NewReader(head))
+	for i := 0; i < lines && sc.Scan(); i++ {
+		buf.WriteString(strings.ReplaceAll(sc.Text(), "\t", "    ") + "\n")
+	}
+	return buf.String()
+}
+
+// isBinary returns true if the data doesn't look like text.
+func isBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	// the last rune may have been cut
+	for len(data) > 0 {
+		r, sz := utf8.DecodeRune(data)
+		if r == utf8.RuneError && sz == 1 && len(data) >= utf8.UTFMax {
+			return true
+		}
+		data = data[sz:]
+	}
+	return false
+}
+
+func previewZip(fsys fs.FS, f fs.File, name string, lines int) string {
+	fi, err := f.Stat()
+	if err != nil {
+		return err.Error()
+	}
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		if fi.Size() > previewZipMax {
+			return "zip archive, too large to preview"
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err.Error()
+		}
+		ra = bytes.NewReader(data)
+	}
+	zr, err := zip.NewReader(ra, fi.Size())
+	if err != nil {
+		return err.Error()
+	}
+	var total uint64
+	for _, zf := range zr.File {
+		total += zf.UncompressedSize64
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d files, %s uncompressed\n", len(zr.File), strings.TrimSpace(humanizeSize(int64(total))))
+	for i, zf := range zr.File {
+		if i == lines-1 {
+			break
+		}
+		fmt.Fprintf(&buf, "%s %s\n", humanizeSize(int64(zf.UncompressedSize64)), path.Clean(zf.Name))
+	}
+	return buf.String()
+}
+
+// printWithPreview prints the listing with the preview pane.
+func (m Model) printWithPreview(w io.Writer) {
+	var lst strings.Builder
+	m.printListing(&lst)
+	listing := strings.TrimSuffix(lst.String(), "\n")
+	switch m.Preview {
+	case PreviewRight:
+		pane := m.Style.Preview.Copy().
+			Width(m.previewWidth()).
+			Height(m.height()).
+			Render(m.previewText(m.previewWidth()-1, m.height()))
+		io.WriteString(w, lipgloss.JoinHorizontal(lipgloss.Top, listing, pane)+"\n")
+	case PreviewBottom:
+		pane := m.Style.Preview.Copy().
+			Width(m.listWidth()).
+			Height(m.previewHeight()).
+			Render(m.previewText(m.listWidth()-1, m.previewHeight()))
+		io.WriteString(w, lipgloss.JoinVertical(lipgloss.Left, listing, pane)+"\n")
+	}
+}
+
+// previewText returns the preview content cut to fit into width×height.
+func (m Model) previewText(width, height int) string {
+	lines := strings.Split(strings.TrimSuffix(m.pv.content, "\n"), "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i := range lines {
+		lines[i] = display.Trunc(lines[i], width)
+	}
+	return strings.Join(lines, "\n")
+}
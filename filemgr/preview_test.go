@@ -0,0 +1,71 @@
+package filemgr
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func zipData(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_loadPreview(t *testing.T) {
+	fsys := fstest.MapFS{
+		"text.txt":     &fstest.MapFile{Data: []byte("line1\n\tline2\nline3\n")},
+		"binary.bin":   &fstest.MapFile{Data: []byte{0x00, 0x01, 0x02, 0x41}},
+		"dir/a.txt":    &fstest.MapFile{},
+		"dir/sub/b":    &fstest.MapFile{},
+		"archive.zip":  &fstest.MapFile{Data: zipData(t, "a.json", "b/c.json")},
+		"notazip.zip":  &fstest.MapFile{Data: []byte("hello")},
+		"dir/empty.md": &fstest.MapFile{},
+	}
+	type args struct {
+		name  string
+		isDir bool
+		lines int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"text", args{"text.txt", false, 2}, "line1\n    line2\n"},
+		{"binary", args{"binary.bin", false, 10}, "00000000  00 01 02 41                                       |...A|\n"},
+		{"dir", args{"dir", true, 10}, "3 entries\na.txt\nempty.md\nsub/\n"},
+		{"dir cut", args{"dir", true, 2}, "3 entries\na.txt\n"},
+		{"zip", args{"archive.zip", false, 10}, "2 files, 14B uncompressed\n    6B a.json\n    8B b/c.json\n"},
+		{"zip extension is not enough", args{"notazip.zip", false, 10}, "hello\n"},
+		{"not found", args{"nope", false, 10}, "open nope: file does not exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, loadPreview(fsys, tt.args.name, tt.args.isDir, tt.args.lines))
+		})
+	}
+}
+
+func Test_isBinary(t *testing.T) {
+	assert.False(t, isBinary([]byte("привет")))
+	assert.False(t, isBinary([]byte("привет")[:3]), "cut rune at the end")
+	assert.True(t, isBinary([]byte{'a', 0, 'b'}))
+	assert.True(t, isBinary([]byte{0xff, 0xfe, 'a', 'b', 'c', 'd'}))
+}
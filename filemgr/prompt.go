@@ -0,0 +1,102 @@
+package filemgr
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies what the text input at the bottom of the listing is
+// being used for.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptMark
+	promptUnmark
+	promptFilter
+	promptMkdir
+	promptCopy
+	promptMove
+	promptGoTo
+)
+
+// openPrompt shows the text input with the given prompt and initial value.
+func (m *Model) openPrompt(kind promptKind, prompt, value string) tea.Cmd {
+	m.prompt = kind
+	m.input.Reset()
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Model) closePrompt() {
+	m.prompt = promptNone
+	m.completions = nil
+	m.gotoErr = nil
+	m.input.Blur()
+}
+
+// updatePrompt processes the key message while the prompt is open.
+func (m Model) updatePrompt(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.Keys.Cancel):
+		if m.prompt == promptFilter {
+			m.applyFilter("")
+		}
+		m.closePrompt()
+		return m, nil
+	case key.Matches(msg, m.Keys.Select):
+		kind, value := m.prompt, m.input.Value()
+		if kind == promptGoTo {
+			// the prompt stays open with the error, if the path is not
+			// found.
+			cmd, err := m.goTo(value)
+			if err != nil {
+				m.completions, m.gotoErr = nil, err
+				return m, nil
+			}
+			m.closePrompt()
+			return m, cmd
+		}
+		m.closePrompt()
+		return m.submitPrompt(kind, value)
+	case m.prompt == promptGoTo && key.Matches(msg, m.Keys.Complete):
+		m.complete()
+		return m, nil
+	}
+	if m.prompt == promptFilter && msg.Type != tea.KeyRunes {
+		// printable keys go to the input.
+		switch {
+		case key.Matches(msg, m.Keys.Up):
+			m.st.Up()
+			return m, nil
+		case key.Matches(msg, m.Keys.Down):
+			m.st.Down(len(m.files))
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	value := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != value {
+		m.completions, m.gotoErr = nil, nil
+	}
+	if m.prompt == promptFilter && m.input.Value() != m.filter {
+		m.applyFilter(m.input.Value())
+	}
+	return m, cmd
+}
+
+// submitPrompt acts on the value entered in the prompt.
+func (m Model) submitPrompt(kind promptKind, value string) (Model, tea.Cmd) {
+	switch kind {
+	case promptMark:
+		m.markGlob(value, true)
+	case promptUnmark:
+		m.markGlob(value, false)
+	case promptMkdir, promptCopy, promptMove:
+		return m, m.submitFileOp(kind, value)
+	}
+	return m, nil
+}
@@ -0,0 +1,83 @@
+package filemgr
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newFilenameInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Save as: "
+	return ti
+}
+
+// updateSaveAs processes the key message while the filename input is
+// focused.
+func (m Model) updateSaveAs(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.Keys.Filename, m.Keys.Cancel):
+		m.filename.Blur()
+		return m, nil
+	case key.Matches(msg, m.Keys.Select):
+		return m, m.save(m.filename.Value())
+	}
+	var cmd tea.Cmd
+	m.filename, cmd = m.filename.Update(msg)
+	return m, cmd
+}
+
+// save returns the command that selects the file with the given name in
+// the current directory, asking for confirmation, if the file exists.
+func (m *Model) save(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	if filepath.Ext(name) == "" {
+		name += m.defaultExt()
+	}
+	target, filter := filepath.Join(m.Directory, name), m.filterName()
+	sel := func() tea.Msg {
+		return WMSelected{Filepath: target, Filter: filter}
+	}
+	if fi, err := fs.Stat(m.FS, filepath.ToSlash(target)); err == nil {
+		if fi.IsDir() {
+			return nil
+		}
+		m.ask("Overwrite "+name+"?", sel)
+		return nil
+	}
+	return sel
+}
+
+// defaultExt returns the extension of the first glob of the form "*.ext",
+// or an empty string.
+func (m Model) defaultExt() string {
+	for _, glob := range m.globs() {
+		ext, ok := strings.CutPrefix(glob, "*.")
+		if ok && ext != "" && !strings.ContainsAny(ext, `*?[\/`) {
+			return "." + ext
+		}
+	}
+	return ""
+}
+
+// prefillFilename puts the name of the file under cursor into the filename
+// input, when the cursor moves over files.
+func (m *Model) prefillFilename() {
+	if !m.SaveAs || m.filename.Focused() || m.st.Cursor >= len(m.files) {
+		return
+	}
+	fi := m.files[m.st.Cursor]
+	if fi.IsDir() || fi.Name() == m.prefilled {
+		return
+	}
+	m.prefilled = fi.Name()
+	m.filename.SetValue(fi.Name())
+	m.filename.CursorEnd()
+}
@@ -0,0 +1,64 @@
+package filemgr
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_defaultExt(t *testing.T) {
+	tests := []struct {
+		name  string
+		globs []string
+		want  string
+	}{
+		{"no globs", nil, ""},
+		{"all files", []string{"*"}, ""},
+		{"first extension", []string{"*.zip", "*.json"}, ".zip"},
+		{"skips patterns", []string{"*.j?on", "data*", "*.txt"}, ".txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{Globs: tt.globs}
+			assert.Equal(t, tt.want, m.defaultExt())
+		})
+	}
+}
+
+func TestModel_SaveAs(t *testing.T) {
+	typeString := func(m Model, s string) Model {
+		for _, r := range s {
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return m
+	}
+	m := New(testfs, ".", 10, "*.txt")
+	m.SaveAs = true
+	m.Focus()
+	m.Select("")
+
+	t.Run("prefilled from the cursor", func(t *testing.T) {
+		m, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown}) // dir2
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})  // file1.txt
+		assert.Equal(t, "file1.txt", m.filename.Value())
+	})
+	t.Run("new file with default extension", func(t *testing.T) {
+		m, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = typeString(m, "new")
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Equal(t, []tea.Msg{WMSelected{Filepath: "new.txt"}}, collectMsgs(cmd))
+	})
+	t.Run("existing file asks for confirmation", func(t *testing.T) {
+		m, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = typeString(m, "file2")
+		m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Nil(t, collectMsgs(cmd))
+		assert.True(t, m.confirm.active())
+
+		_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		assert.Nil(t, collectMsgs(cmd))
+		_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		assert.Equal(t, []tea.Msg{WMSelected{Filepath: "file2.txt"}}, collectMsgs(cmd))
+	})
+}
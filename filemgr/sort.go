@@ -0,0 +1,147 @@
+package filemgr
+
+import (
+	"cmp"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SortKey is the key that the file listing is sorted by.
+type SortKey int
+
+const (
+	SortName    SortKey = iota // case-insensitive name
+	SortNatural                // name, with numbers compared by value
+	SortSize
+	SortModTime
+	SortExt
+	numSortKeys
+)
+
+var sortKeyNames = [numSortKeys]string{
+	SortName:    "name",
+	SortNatural: "natural",
+	SortSize:    "size",
+	SortModTime: "time",
+	SortExt:     "extension",
+}
+
+func (k SortKey) String() string {
+	if k < 0 || k >= numSortKeys {
+		return "unknown"
+	}
+	return sortKeyNames[k]
+}
+
+// Next returns the next sort key, wrapping around after the last one.
+func (k SortKey) Next() SortKey {
+	return (k + 1) % numSortKeys
+}
+
+// sortFiles sorts files in place.  Special directories, such as "..", are
+// always placed first.
+func sortFiles(files []fs.FileInfo, key SortKey, desc bool, dirsFirst bool) {
+	slices.SortStableFunc(files, func(a, b fs.FileInfo) int {
+		if c := cmpBool(!markable(a), !markable(b)); c != 0 {
+			return c
+		} else if !markable(a) {
+			return cmp.Compare(a.Name(), b.Name()) // "." before ".."
+		}
+		if dirsFirst {
+			if c := cmpBool(a.IsDir(), b.IsDir()); c != 0 {
+				return c
+			}
+		}
+		c := compareFiles(a, b, key)
+		if c == 0 && key != SortName {
+			c = compareFiles(a, b, SortName)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// cmpBool orders true before false.
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func compareFiles(a, b fs.FileInfo, key SortKey) int {
+	switch key {
+	case SortNatural:
+		return naturalCompare(a.Name(), b.Name())
+	case SortSize:
+		return cmp.Compare(a.Size(), b.Size())
+	case SortModTime:
+		return a.ModTime().Compare(b.ModTime())
+	case SortExt:
+		return cmp.Compare(strings.ToLower(filepath.Ext(a.Name())), strings.ToLower(filepath.Ext(b.Name())))
+	default:
+		if c := cmp.Compare(strings.ToLower(a.Name()), strings.ToLower(b.Name())); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name(), b.Name())
+	}
+}
+
+// naturalCompare compares strings case-insensitively, treating runs of
+// digits as numbers, so that "file2" sorts before "file10".
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		var ca, cb string
+		ca, a = nextChunk(a)
+		cb, b = nextChunk(b)
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if c := cmp.Compare(len(na), len(nb)); c != 0 {
+				return c
+			}
+			if c := cmp.Compare(na, nb); c != 0 {
+				return c
+			}
+			// same value, fewer leading zeros first
+			if c := cmp.Compare(len(ca), len(cb)); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := cmp.Compare(strings.ToLower(ca), strings.ToLower(cb)); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// nextChunk splits s into the leading run of digits or non-digits and the
+// rest of the string.
+func nextChunk(s string) (chunk, rest string) {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// resort sorts the listing according to the current sort settings, keeping
+// the cursor on the same file.
+func (m *Model) resort() {
+	cur := m.current()
+	m.populate(m.entries)
+	m.focusName(cur)
+}
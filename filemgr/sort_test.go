@@ -0,0 +1,77 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_naturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"file2", "file2", 0},
+		{"File2", "file3", -1},
+		{"file02", "file2", 1},
+		{"a", "a1", -1},
+		{"export-2024-01-9.zip", "export-2024-01-10.zip", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, naturalCompare(tt.a, tt.b))
+		})
+	}
+}
+
+func Test_sortFiles(t *testing.T) {
+	var fsys = fstest.MapFS{
+		"b10.txt": &fstest.MapFile{Data: []byte("12345"), ModTime: time.Unix(3, 0)},
+		"b9.json": &fstest.MapFile{Data: []byte("1"), ModTime: time.Unix(1, 0)},
+		"A.zip":   &fstest.MapFile{Data: []byte("123"), ModTime: time.Unix(2, 0)},
+		"dir":     &fstest.MapFile{Mode: fs.ModeDir},
+	}
+	files := func() []fs.FileInfo {
+		return []fs.FileInfo{
+			must(fs.Stat(fsys, "b10.txt")),
+			must(fs.Stat(fsys, "dir")),
+			must(fs.Stat(fsys, "b9.json")),
+			specialDir{".."},
+			must(fs.Stat(fsys, "A.zip")),
+		}
+	}
+	type args struct {
+		key       SortKey
+		desc      bool
+		dirsFirst bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{"name", args{SortName, false, false}, []string{"..", "A.zip", "b10.txt", "b9.json", "dir"}},
+		{"name desc", args{SortName, true, false}, []string{"..", "dir", "b9.json", "b10.txt", "A.zip"}},
+		{"name dirs first", args{SortName, false, true}, []string{"..", "dir", "A.zip", "b10.txt", "b9.json"}},
+		{"natural", args{SortNatural, false, true}, []string{"..", "dir", "A.zip", "b9.json", "b10.txt"}},
+		{"size", args{SortSize, false, false}, []string{"..", "dir", "b9.json", "A.zip", "b10.txt"}},
+		{"time", args{SortModTime, false, true}, []string{"..", "dir", "b9.json", "A.zip", "b10.txt"}},
+		{"extension", args{SortExt, false, true}, []string{"..", "dir", "b9.json", "b10.txt", "A.zip"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ff := files()
+			sortFiles(ff, tt.args.key, tt.args.desc, tt.args.dirsFirst)
+			var got []string
+			for _, fi := range ff {
+				got = append(got, fi.Name())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package filemgr
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Style is the style of the file manager.  Files in the listing are styled
+// by the type, then by the extension: Symlink and Broken take precedence,
+// then Directory, Special, Executable and Ext, and the rest are Normal.
+type Style struct {
+	Normal     lipgloss.Style
+	Directory  lipgloss.Style
+	Executable lipgloss.Style
+	Special    lipgloss.Style // named pipes, sockets and devices
+	Symlink    lipgloss.Style
+	Broken     lipgloss.Style // symbolic link to nowhere
+	// Ext are the styles of the files by the lower case extension with the
+	// dot, i.e. ".json".  Compound extensions, such as ".tar.gz", take
+	// precedence over the last extension.
+	Ext        map[string]lipgloss.Style
+	Inverted   lipgloss.Style
+	Marked     lipgloss.Style
+	Match      lipgloss.Style // filter match highlight
+	Preview    lipgloss.Style
+	Breadcrumb lipgloss.Style
+	Error      lipgloss.Style
+}
+
+// DefaultStyle returns the default style.
+func DefaultStyle() Style {
+	var (
+		archive = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+		image   = lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
+		data    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	)
+	return Style{
+		Normal:     lipgloss.NewStyle().Foreground(lipgloss.Color("7")),
+		Directory:  lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true),
+		Executable: lipgloss.NewStyle().Foreground(lipgloss.Color("10")),
+		Special:    lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+		Symlink:    lipgloss.NewStyle().Foreground(lipgloss.Color("6")),
+		Broken:     lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Strikethrough(true),
+		Ext: map[string]lipgloss.Style{
+			".zip": archive, ".tar": archive, ".tar.gz": archive, ".tgz": archive,
+			".gz": archive, ".bz2": archive, ".xz": archive, ".7z": archive, ".rar": archive,
+			".png": image, ".jpg": image, ".jpeg": image, ".gif": image,
+			".bmp": image, ".svg": image, ".webp": image,
+			".json": data, ".yaml": data, ".yml": data, ".toml": data, ".xml": data, ".csv": data,
+		},
+		Inverted:   lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Background(lipgloss.Color("240")),
+		Marked:     lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true),
+		Match:      lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Underline(true),
+		Preview:    lipgloss.NewStyle().Foreground(lipgloss.Color("250")).PaddingLeft(1),
+		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("12")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true),
+	}
+}
+
+// file returns the style of the file in the listing.
+func (s Style) file(fi fs.FileInfo) lipgloss.Style {
+	if li, ok := asLink(fi); ok {
+		if li.broken {
+			return s.Broken
+		}
+		return s.Symlink
+	}
+	mode := fi.Mode()
+	switch {
+	case fi.IsDir():
+		return s.Directory
+	case mode&(fs.ModeNamedPipe|fs.ModeSocket|fs.ModeDevice|fs.ModeCharDevice) != 0:
+		return s.Special
+	case mode.IsRegular() && mode&0o111 != 0:
+		return s.Executable
+	}
+	if st, ok := s.ext(filepath.Base(fi.Name())); ok {
+		return st
+	}
+	return s.Normal
+}
+
+// ext returns the style for the longest extension of the name.
+func (s Style) ext(name string) (lipgloss.Style, bool) {
+	name = strings.ToLower(name)
+	for i := 1; i < len(name); i++ {
+		if name[i] != '.' {
+			continue
+		}
+		if st, ok := s.Ext[name[i:]]; ok {
+			return st, true
+		}
+	}
+	return lipgloss.Style{}, false
+}
@@ -0,0 +1,76 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle_file(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dir":        &fstest.MapFile{Mode: fs.ModeDir},
+		"run.sh":     &fstest.MapFile{Mode: 0o755},
+		"fifo":       &fstest.MapFile{Mode: fs.ModeNamedPipe},
+		"data.JSON":  &fstest.MapFile{},
+		"a.tar.gz":   &fstest.MapFile{},
+		"b.gz":       &fstest.MapFile{},
+		"readme":     &fstest.MapFile{},
+		".gitignore": &fstest.MapFile{},
+	}
+	s := DefaultStyle()
+	s.Ext[".tar.gz"] = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	tests := []struct {
+		name string
+		want lipgloss.Style
+	}{
+		{"dir", s.Directory},
+		{"run.sh", s.Executable},
+		{"fifo", s.Special},
+		{"data.JSON", s.Ext[".json"]},
+		{"a.tar.gz", s.Ext[".tar.gz"]},
+		{"b.gz", s.Ext[".gz"]},
+		{"readme", s.Normal},
+		{".gitignore", s.Normal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi := must(fs.Stat(fsys, tt.name))
+			assert.Equal(t, tt.want, s.file(fi))
+		})
+	}
+	fi := must(fs.Stat(fsys, "readme"))
+	assert.Equal(t, s.Symlink, s.file(linkInfo{FileInfo: fi}))
+	assert.Equal(t, s.Broken, s.file(treeNode{FileInfo: linkInfo{FileInfo: fi, broken: true}, rel: "x/readme"}))
+}
+
+func TestStyle_LSColors(t *testing.T) {
+	base := DefaultStyle()
+	s := base.LSColors("rs=0:di=01;34:ln=36:ex=38;5;208:or=48;2;255;0;16:*.JSON=33:*.tar.gz=1;31:mi=00:bogus:fi=x")
+
+	assert.True(t, s.Directory.GetBold())
+	assert.Equal(t, lipgloss.Color("4"), s.Directory.GetForeground())
+	assert.Equal(t, lipgloss.Color("6"), s.Symlink.GetForeground())
+	assert.Equal(t, lipgloss.Color("208"), s.Executable.GetForeground())
+	assert.Equal(t, lipgloss.Color("#ff0010"), s.Broken.GetBackground())
+	assert.Equal(t, lipgloss.Color("3"), s.Ext[".json"].GetForeground())
+	assert.Equal(t, lipgloss.Color("1"), s.Ext[".tar.gz"].GetForeground())
+	assert.Equal(t, base.Normal, s.Normal, "malformed entry is ignored")
+	assert.Equal(t, lipgloss.Color("9"), base.Ext[".tar.gz"].GetForeground(), "the base style is not modified")
+}
+
+func Test_sgrStyle(t *testing.T) {
+	st, err := sgrStyle("1;4;93;100")
+	assert.NoError(t, err)
+	assert.True(t, st.GetBold())
+	assert.True(t, st.GetUnderline())
+	assert.Equal(t, lipgloss.Color("11"), st.GetForeground())
+	assert.Equal(t, lipgloss.Color("8"), st.GetBackground())
+
+	for _, codes := range []string{"38;5", "38;2;1;2", "48;5;256", "1;x", ""} {
+		_, err := sgrStyle(codes)
+		assert.Error(t, err, codes)
+	}
+}
@@ -0,0 +1,105 @@
+package filemgr
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ReadLinkFS is the file system, that supports symbolic links.  It has the
+// shape of fs.ReadLinkFS from Go 1.25, which os.DirFS implements.  On other
+// file systems the links are not detected.
+type ReadLinkFS interface {
+	fs.FS
+	// ReadLink returns the destination of the symbolic link.
+	ReadLink(name string) (string, error)
+	// Lstat returns the FileInfo of the file, without following the link.
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+var (
+	_ ReadLinkFS = OSFS{}
+	_ ReadLinkFS = (*ArchiveFS)(nil)
+)
+
+// linkInfo is the FileInfo of the symbolic link.  The link to a directory
+// reports IsDir, so it is listed and entered as a directory.
+type linkInfo struct {
+	fs.FileInfo        // of the link itself
+	target      string // destination, if known
+	dir         bool
+	broken      bool // destination doesn't exist
+}
+
+func (l linkInfo) IsDir() bool {
+	return l.dir
+}
+
+// resolveLink returns the linkInfo, if fi at name is a symbolic link,
+// otherwise it returns fi.
+func resolveLink(fsys fs.FS, name string, fi fs.FileInfo) fs.FileInfo {
+	if fi.Mode()&fs.ModeSymlink == 0 {
+		return fi
+	}
+	li := linkInfo{FileInfo: fi}
+	if rl, ok := fsys.(ReadLinkFS); ok {
+		li.target, _ = rl.ReadLink(name)
+	}
+	if st, err := fs.Stat(fsys, name); err != nil {
+		li.broken = true
+	} else {
+		li.dir = st.IsDir()
+	}
+	return li
+}
+
+// asLink returns the linkInfo, if the file in the listing is a symbolic
+// link.
+func asLink(fi fs.FileInfo) (linkInfo, bool) {
+	if n, ok := fi.(treeNode); ok {
+		fi = n.FileInfo
+	}
+	li, ok := fi.(linkInfo)
+	return li, ok
+}
+
+func (o OSFS) ReadLink(name string) (string, error) {
+	p, err := o.join("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	target, err := os.Readlink(p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(target), nil
+}
+
+func (o OSFS) Lstat(name string) (fs.FileInfo, error) {
+	p, err := o.join("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(p)
+}
+
+// ReadLink returns the destination of the symbolic link, if the underlying
+// FS supports links.  Links inside archives are not supported.
+func (a *ArchiveFS) ReadLink(name string) (string, error) {
+	rl, ok := a.fsys.(ReadLinkFS)
+	if archive, _ := a.Split(name); !ok || archive != "" {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.ErrUnsupported}
+	}
+	return rl.ReadLink(name)
+}
+
+// Lstat returns the FileInfo of the file without following the link, if
+// the underlying FS supports links, otherwise it is the same as Stat.
+func (a *ArchiveFS) Lstat(name string) (fs.FileInfo, error) {
+	rl, ok := a.fsys.(ReadLinkFS)
+	if archive, _ := a.Split(name); !ok || archive != "" {
+		return a.Stat(name)
+	}
+	return rl.Lstat(name)
+}
@@ -0,0 +1,71 @@
+package filemgr
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkDir returns the directory with the symbolic links to a directory, to
+// a file and to nowhere.
+func linkDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "real"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "real", "f.txt"), []byte("f"), 0o644))
+	for link, target := range map[string]string{
+		"linkdir":    "real",
+		"link.txt":   "real/f.txt",
+		"broken.txt": "nowhere",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skip("symlinks are not supported:", err)
+		}
+	}
+	return dir
+}
+
+func TestModel_symlinks(t *testing.T) {
+	dir := linkDir(t)
+	for name, fsys := range map[string]fs.FS{
+		"OSFS":      DirFS(dir),
+		"ArchiveFS": NewArchiveFS(DirFS(dir)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := New(fsys, ".", 10, "*.txt")
+			m.Width = 60
+			m.Focus()
+			m = run(m, m.load())
+			assert.Equal(t, []string{"linkdir", "real", "broken.txt", "link.txt"}, fileNames(m.files), "linked directory is a directory")
+
+			view := m.View()
+			assert.Contains(t, view, "linkdir -> real")
+			assert.Contains(t, view, "link.txt -> real/f.txt")
+			assert.Contains(t, view, "broken.txt -> nowhere")
+
+			li, ok := asLink(m.files[2])
+			assert.True(t, ok)
+			assert.True(t, li.broken)
+
+			// the linked directory is entered
+			m.Select("linkdir")
+			m = run(m, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyEnter} })
+			assert.Equal(t, "linkdir", m.Directory)
+			assert.Equal(t, []string{"..", "f.txt"}, fileNames(m.files))
+		})
+	}
+}
+
+func Test_resolveLink(t *testing.T) {
+	fsys := DirFS(linkDir(t))
+	fi := must(fsys.Lstat("real"))
+	assert.Equal(t, fi, resolveLink(fsys, "real", fi), "not a link")
+
+	fi = must(fsys.Lstat("link.txt"))
+	assert.Equal(t, linkInfo{FileInfo: fi, target: "real/f.txt"}, resolveLink(fsys, "link.txt", fi))
+}
@@ -0,0 +1,207 @@
+package filemgr
+
+import (
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Indentation guides of the tree.
+const (
+	guideBranch = "├─ "
+	guideLast   = "└─ "
+	guideLine   = "│  "
+	guideBlank  = "   "
+)
+
+// treeNode is the entry of the directory expanded in the tree.  Name
+// returns the path relative to the current directory, so the entry is
+// located, marked and selected the same way as the top level ones.
+type treeNode struct {
+	fs.FileInfo
+	rel    string
+	prefix string // indentation guides
+}
+
+func (n treeNode) Name() string {
+	return n.rel
+}
+
+// wmExpanded is sent when the directory expanded in the tree is read.
+type wmExpanded struct {
+	model uint64
+	dir   string // path of the directory
+	files []fs.FileInfo
+	err   error
+}
+
+// displayName returns the name of the file as shown in the listing, with
+// the destination of the symbolic link.
+func displayName(fi fs.FileInfo) string {
+	name := fi.Name()
+	if n, ok := fi.(treeNode); ok {
+		name = n.prefix + filepath.Base(n.rel)
+	}
+	if li, ok := asLink(fi); ok && li.target != "" {
+		name += " -> " + li.target
+	}
+	return name
+}
+
+// expandable returns true if the file can be expanded in the tree.
+func (m Model) expandable(fi fs.FileInfo) bool {
+	return markable(fi) && (fi.IsDir() || m.isArchive(fi))
+}
+
+// isExpanded returns true if the directory is expanded in the tree.
+func (m Model) isExpanded(fi fs.FileInfo) bool {
+	_, ok := m.expanded[m.path(fi)]
+	return ok
+}
+
+// tree inserts the children of the expanded directories after them.
+func (m Model) tree(files []fs.FileInfo) []fs.FileInfo {
+	if len(m.expanded) == 0 {
+		return files
+	}
+	out := make([]fs.FileInfo, 0, len(files))
+	for _, fi := range files {
+		out = append(out, fi)
+		out = m.appendChildren(out, fi, "")
+	}
+	return out
+}
+
+// appendChildren appends the visible children of the parent, if it is
+// expanded, and their expanded children, to out.
+func (m Model) appendChildren(out []fs.FileInfo, parent fs.FileInfo, indent string) []fs.FileInfo {
+	if !m.expandable(parent) {
+		return out
+	}
+	var children []fs.FileInfo
+	for _, fi := range m.expanded[m.path(parent)] {
+		n := treeNode{FileInfo: fi, rel: filepath.Join(parent.Name(), fi.Name())}
+		if m.visible(n) {
+			children = append(children, n)
+		}
+	}
+	sortFiles(children, m.Sort, m.SortDesc, m.DirsFirst)
+	for i, fi := range children {
+		n := fi.(treeNode)
+		guide, next := guideBranch, guideLine
+		if i == len(children)-1 {
+			guide, next = guideLast, guideBlank
+		}
+		n.prefix = indent + guide
+		out = append(out, n)
+		out = m.appendChildren(out, n, indent+next)
+	}
+	return out
+}
+
+// expand expands the directory under cursor, or moves the cursor to its
+// first child, if it is already expanded.
+func (m *Model) expand() tea.Cmd {
+	if len(m.files) == 0 || !m.expandable(m.files[m.st.Cursor]) {
+		return nil
+	}
+	fi := m.files[m.st.Cursor]
+	if m.isExpanded(fi) {
+		if next := m.st.Cursor + 1; next < len(m.files) && filepath.Dir(m.files[next].Name()) == fi.Name() {
+			m.st.Down(len(m.files))
+		}
+		return nil
+	}
+	if m.expanded == nil {
+		m.expanded = make(map[string][]fs.FileInfo)
+	}
+	dir := m.path(fi)
+	m.expanded[dir] = nil // children are shown when read
+	return m.expandCmd(dir)
+}
+
+// collapse collapses the directory under cursor, or moves the cursor to
+// the parent directory.
+func (m *Model) collapse() {
+	if len(m.files) == 0 {
+		return
+	}
+	fi := m.files[m.st.Cursor]
+	if m.isExpanded(fi) {
+		m.collapseDir(m.path(fi))
+		m.populate(m.entries)
+		m.focusName(fi.Name())
+		return
+	}
+	if _, ok := fi.(treeNode); ok {
+		m.focusName(filepath.Dir(fi.Name()))
+	}
+}
+
+// collapseDir forgets the children of the directory and its expanded
+// subdirectories.
+func (m *Model) collapseDir(dir string) {
+	for name := range m.expanded {
+		if name == dir || within(dir, name) {
+			delete(m.expanded, name)
+		}
+	}
+}
+
+// within returns true if name is inside dir.
+func within(dir, name string) bool {
+	if isRoot(dir) {
+		return true
+	}
+	return strings.HasPrefix(name, dir+string(filepath.Separator))
+}
+
+// expandCmd returns the command that reads the children of the directory.
+func (m Model) expandCmd(dir string) tea.Cmd {
+	var (
+		model = m.id
+		fsys  = m.FS
+	)
+	mt, err := m.matcher()
+	return func() tea.Msg {
+		if err != nil {
+			return wmExpanded{model: model, dir: dir, err: err}
+		}
+		files, err := readEntries(fsys, dir, mt)
+		return wmExpanded{model: model, dir: dir, files: files, err: err}
+	}
+}
+
+// refreshTreeCmd returns the command that rereads the expanded directories
+// within the current directory.
+func (m Model) refreshTreeCmd() tea.Cmd {
+	if !m.Tree {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for dir := range m.expanded {
+		if within(m.Directory, dir) {
+			cmds = append(cmds, m.expandCmd(dir))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// expandDone processes the children of the expanded directory.
+func (m *Model) expandDone(msg wmExpanded) {
+	if _, ok := m.expanded[msg.dir]; !ok {
+		return // collapsed while reading
+	}
+	if msg.err != nil {
+		slog.Error("expand", "dir", msg.dir, "err", msg.err)
+		delete(m.expanded, msg.dir)
+		return
+	}
+	cur := m.current()
+	m.expanded[msg.dir] = msg.files
+	m.populate(m.entries)
+	m.focusName(cur)
+}
@@ -0,0 +1,88 @@
+package filemgr
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+var treefs = fstest.MapFS{
+	"a/b/deep.txt": &fstest.MapFile{Data: []byte("deep")},
+	"a/x.txt":      &fstest.MapFile{Data: []byte("x")},
+	"a/.hidden":    &fstest.MapFile{Data: []byte("hidden")},
+	"c/y.txt":      &fstest.MapFile{Data: []byte("y")},
+	"top.txt":      &fstest.MapFile{Data: []byte("top")},
+}
+
+func TestModel_Tree(t *testing.T) {
+	press := func(m Model, msg tea.KeyMsg) Model {
+		return run(m, func() tea.Msg { return msg })
+	}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+
+	m := New(treefs, ".", 10, "*")
+	m.Tree = true
+	m.Focus()
+	m = run(m, m.load())
+	assert.Equal(t, []string{"a", "c", "top.txt"}, fileNames(m.files))
+
+	// expand "a" and "a/b"
+	m = press(m, right)
+	assert.Equal(t, []string{"a", "a/b", "a/x.txt", "c", "top.txt"}, fileNames(m.files))
+	assert.Equal(t, "a", m.current(), "cursor stays")
+	m = press(m, right)
+	assert.Equal(t, "a/b", m.current(), "moves to the first child")
+	m = press(m, right)
+	assert.Equal(t, []string{"a", "a/b", "a/b/deep.txt", "a/x.txt", "c", "top.txt"}, fileNames(m.files))
+
+	view := m.View()
+	assert.Contains(t, view, "├─ b")
+	assert.Contains(t, view, "│  └─ deep.txt")
+	assert.Contains(t, view, "└─ x.txt")
+
+	// left moves to the parent, then collapses it with the children
+	m.Select("a/b/deep.txt")
+	m = press(m, left)
+	assert.Equal(t, "a/b", m.current())
+	m = press(m, left)
+	m = press(m, left)
+	assert.Equal(t, "a", m.current())
+	m = press(m, left)
+	assert.Equal(t, []string{"a", "c", "top.txt"}, fileNames(m.files))
+	assert.Empty(t, m.expanded)
+
+	// hidden files are hidden in the subdirectories too
+	m = press(m, right)
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	assert.Contains(t, fileNames(m.files), "a/.hidden")
+
+	// entering the nested directory, back goes up one level at a time
+	m.Select("a/b")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "a/b", m.Directory)
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "a", m.Directory)
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, ".", m.Directory)
+	assert.Contains(t, fileNames(m.files), "a/x.txt", "expansion is kept")
+
+	// selecting the nested file reports its path
+	m.Select("a/x.txt")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, collectMsgs(cmd), WMSelected{Filepath: "a/x.txt"})
+
+	// toggling the tree off shows the flat listing
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlT})
+	assert.False(t, m.Tree)
+	assert.Equal(t, []string{"a", "c", "top.txt"}, fileNames(m.files))
+}
+
+func Test_displayName(t *testing.T) {
+	fi := must(fs.Stat(treefs, "a/x.txt"))
+	assert.Equal(t, "x.txt", displayName(fi))
+	assert.Equal(t, "│  └─ x.txt", displayName(treeNode{FileInfo: fi, rel: "a/b/x.txt", prefix: "│  └─ "}))
+}
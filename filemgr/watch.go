@@ -0,0 +1,89 @@
+package filemgr
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wmWatch is sent by the directory watcher after polling the directory.
+type wmWatch struct {
+	model uint64
+	gen   uint64 // watcher generation
+	dir   string
+	sum   uint64 // snapshot of the directory
+	err   error
+}
+
+// watchCmd returns the command that polls the current directory after the
+// Watch interval.  Any watcher started earlier is stopped.
+func (m *Model) watchCmd() tea.Cmd {
+	if m.Watch <= 0 {
+		return nil
+	}
+	m.watchGen++
+	return m.pollCmd()
+}
+
+func (m Model) pollCmd() tea.Cmd {
+	var (
+		model = m.id
+		gen   = m.watchGen
+		fsys  = m.FS
+		dir   = m.Directory
+	)
+	mt, err := m.matcher()
+	return tea.Tick(m.Watch, func(time.Time) tea.Msg {
+		if err != nil {
+			return wmWatch{model: model, gen: gen, dir: dir, err: err}
+		}
+		files, err := readDir(fsys, dir, mt)
+		return wmWatch{model: model, gen: gen, dir: dir, sum: snapshot(files), err: err}
+	})
+}
+
+// watched processes the result of polling.  It returns the command to
+// reload the directory, if it has changed, or to continue polling.
+func (m *Model) watched(msg wmWatch) tea.Cmd {
+	if msg.gen != m.watchGen || msg.dir != m.Directory || m.Watch <= 0 {
+		return nil // stale watcher
+	}
+	if msg.err != nil {
+		slog.Debug("watch", "dir", msg.dir, "err", msg.err)
+		return m.pollCmd()
+	}
+	if m.loading || msg.sum == snapshot(m.entries) {
+		return m.pollCmd()
+	}
+	slog.Debug("watch: directory changed", "dir", msg.dir)
+	return m.load()
+}
+
+// snapshot returns the checksum of the names, sizes, modes and modification
+// times of the files, that doesn't depend on the order of files.
+func snapshot(files []fs.FileInfo) uint64 {
+	var (
+		sum uint64
+		h   = fnv.New64a()
+		buf [8]byte
+	)
+	for _, fi := range files {
+		if !markable(fi) {
+			continue
+		}
+		h.Reset()
+		h.Write([]byte(fi.Name()))
+		binary.LittleEndian.PutUint64(buf[:], uint64(fi.Size()))
+		h.Write(buf[:])
+		binary.LittleEndian.PutUint64(buf[:], uint64(fi.ModTime().UnixNano()))
+		h.Write(buf[:])
+		binary.LittleEndian.PutUint64(buf[:], uint64(fi.Mode()))
+		h.Write(buf[:])
+		sum += h.Sum64()
+	}
+	return sum
+}
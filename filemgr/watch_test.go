@@ -0,0 +1,51 @@
+package filemgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModel_watched(t *testing.T) {
+	fsys := NewMemFS()
+	require.NoError(t, fsys.WriteFile("a.txt", nil, 0o644))
+	require.NoError(t, fsys.WriteFile("c.txt", nil, 0o644))
+
+	m := New(fsys, ".", 10, "*")
+	m.Watch = time.Millisecond
+	m.Focus()
+	m, cmd := m.Update(m.load()())
+	assert.NotNil(t, cmd, "watcher should be started")
+	gen := m.watchGen
+	m.Select("c.txt")
+
+	poll := func() wmWatch {
+		t.Helper()
+		files, err := readDir(fsys, ".", globMatcher("*"))
+		require.NoError(t, err)
+		return wmWatch{model: m.id, gen: gen, dir: ".", sum: snapshot(files)}
+	}
+
+	// no changes, continue polling
+	cmd = m.watched(poll())
+	if assert.NotNil(t, cmd) {
+		assert.IsType(t, wmWatch{}, cmd())
+	}
+
+	// stale watcher stops
+	assert.Nil(t, m.watched(wmWatch{model: m.id, gen: gen - 1, dir: "."}))
+
+	// file added: the listing is reloaded, and cursor stays on c.txt
+	require.NoError(t, fsys.WriteFile("b.txt", nil, 0o644))
+	cmd = m.watched(poll())
+	if assert.NotNil(t, cmd) {
+		msg := cmd()
+		assert.IsType(t, wmReadDir{}, msg)
+		m, _ = m.Update(msg)
+	}
+	assert.Len(t, m.files, 3)
+	assert.Equal(t, "c.txt", m.current())
+	assert.Greater(t, m.watchGen, gen, "new watcher should be started")
+}
@@ -0,0 +1,291 @@
+package filemgr
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WriteFS is a file system that can be modified.  All names are
+// slash-separated paths, as in fs.FS.
+type WriteFS interface {
+	fs.FS
+	// Mkdir creates a directory.
+	Mkdir(name string, perm fs.FileMode) error
+	// Rename renames (moves) a file or a directory.
+	Rename(oldname, newname string) error
+	// Remove removes a file or a directory with all its contents.
+	Remove(name string) error
+	// Copy copies a file or a directory with all its contents.  It fails
+	// if dst exists.
+	Copy(src, dst string) error
+}
+
+var (
+	_ WriteFS = OSFS{}
+	_ WriteFS = (*MemFS)(nil)
+)
+
+// OSFS is the WriteFS for the tree of files of the operating system rooted
+// at the directory.
+type OSFS struct {
+	fs.FS
+	root string
+}
+
+// DirFS returns the WriteFS for the tree of files rooted at the directory
+// dir.  Reading is done via os.DirFS.
+func DirFS(dir string) OSFS {
+	return OSFS{FS: os.DirFS(dir), root: dir}
+}
+
+// join returns the OS path of the name.
+func (o OSFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(o.root, filepath.FromSlash(name)), nil
+}
+
+func (o OSFS) Mkdir(name string, perm fs.FileMode) error {
+	p, err := o.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.Mkdir(p, perm)
+}
+
+func (o OSFS) Rename(oldname, newname string) error {
+	src, err := o.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+	dst, err := o.join("rename", newname)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+	}
+	return os.Rename(src, dst)
+}
+
+func (o OSFS) Remove(name string) error {
+	p, err := o.join("remove", name)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := os.Lstat(p); err != nil {
+		return err
+	}
+	return os.RemoveAll(p)
+}
+
+func (o OSFS) Copy(src, dst string) error {
+	if _, err := o.join("copy", src); err != nil {
+		return err
+	}
+	d, err := o.join("copy", dst)
+	if err != nil {
+		return err
+	}
+	if src == "." || strings.HasPrefix(dst, src+"/") {
+		// the walk would descend into the copy being made.
+		return &fs.PathError{Op: "copy", Path: dst, Err: fs.ErrInvalid}
+	}
+	if _, err := os.Lstat(d); err == nil {
+		return &fs.PathError{Op: "copy", Path: dst, Err: fs.ErrExist}
+	}
+	return fs.WalkDir(o.FS, src, func(name string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(d, filepath.FromSlash(strings.TrimPrefix(name, src)))
+		fi, err := de.Info()
+		if err != nil {
+			return err
+		}
+		if de.IsDir() {
+			return os.Mkdir(target, fi.Mode().Perm())
+		}
+		return o.copyFile(name, target, fi.Mode().Perm())
+	})
+}
+
+func (o OSFS) copyFile(name string, target string, perm fs.FileMode) error {
+	in, err := o.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// MemFS is the in-memory WriteFS.  It is safe for concurrent use.
+type MemFS struct {
+	mu sync.RWMutex
+	m  mapFS
+}
+
+// NewMemFS returns an empty in-memory file system.
+func NewMemFS() *MemFS {
+	return &MemFS{m: make(mapFS)}
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.m.Open(name)
+}
+
+// WriteFile creates or replaces the file with the given data.  Missing
+// parent directories are created, and stay when the file is removed.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if fi, err := fs.Stat(m.m, name); err == nil && fi.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: errors.New("is a directory")}
+	}
+	var parents []string
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		f, ok := m.m[dir]
+		if ok && !f.Mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: name, Err: errors.New("not a directory")}
+		}
+		if !ok {
+			parents = append(parents, dir)
+		}
+	}
+	now := time.Now()
+	for _, dir := range parents {
+		m.m[dir] = &mapFile{Mode: fs.ModeDir | 0o755, ModTime: now}
+	}
+	m.m[name] = &mapFile{Data: slices.Clone(data), Mode: perm.Perm(), ModTime: now}
+	return nil
+}
+
+func (m *MemFS) Mkdir(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkNew("mkdir", name); err != nil {
+		return err
+	}
+	m.m[name] = &mapFile{Mode: fs.ModeDir | perm.Perm(), ModTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkExists("rename", oldname); err != nil {
+		return err
+	}
+	if err := m.checkNew("rename", newname); err != nil {
+		return err
+	}
+	if newname == oldname || strings.HasPrefix(newname, oldname+"/") {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+	for name, f := range m.tree(oldname) {
+		delete(m.m, name)
+		m.m[newname+strings.TrimPrefix(name, oldname)] = f
+	}
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkExists("remove", name); err != nil {
+		return err
+	}
+	for p := range m.tree(name) {
+		delete(m.m, p)
+	}
+	return nil
+}
+
+func (m *MemFS) Copy(src, dst string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkExists("copy", src); err != nil {
+		return err
+	}
+	if err := m.checkNew("copy", dst); err != nil {
+		return err
+	}
+	if strings.HasPrefix(dst, src+"/") {
+		return &fs.PathError{Op: "copy", Path: dst, Err: fs.ErrInvalid}
+	}
+	for name, f := range m.tree(src) {
+		cp := *f
+		cp.Data = slices.Clone(f.Data)
+		m.m[dst+strings.TrimPrefix(name, src)] = &cp
+	}
+	return nil
+}
+
+// tree returns the entries of the map for the name and all its
+// descendants.  If the name is an implicit directory, it is added to the
+// result.
+func (m *MemFS) tree(name string) map[string]*mapFile {
+	t := make(map[string]*mapFile)
+	for p, f := range m.m {
+		if p == name || strings.HasPrefix(p, name+"/") {
+			t[p] = f
+		}
+	}
+	if _, ok := t[name]; !ok {
+		t[name] = &mapFile{Mode: fs.ModeDir | 0o755, ModTime: time.Now()}
+	}
+	return t
+}
+
+// checkExists returns an error if the file does not exist.
+func (m *MemFS) checkExists(op, name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := fs.Stat(m.m, name); err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return nil
+}
+
+// checkNew returns an error if the file exists, or its parent directory
+// doesn't.
+func (m *MemFS) checkNew(op, name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := fs.Stat(m.m, name); err == nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrExist}
+	}
+	if dir := path.Dir(name); dir != "." {
+		if fi, err := fs.Stat(m.m, dir); err != nil || !fi.IsDir() {
+			return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package filemgr
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWriteFS runs the WriteFS conformance checks.  fsys must contain
+// "a.txt" with "a" and an empty "dir".
+func testWriteFS(t *testing.T, fsys WriteFS) {
+	t.Helper()
+	// mkdir
+	require.NoError(t, fsys.Mkdir("new", 0o755))
+	assert.ErrorIs(t, fsys.Mkdir("new", 0o755), fs.ErrExist)
+	assert.ErrorIs(t, fsys.Mkdir("nope/new", 0o755), fs.ErrNotExist)
+
+	// copy file and directory
+	require.NoError(t, fsys.Copy("a.txt", "dir/b.txt"))
+	require.NoError(t, fsys.Copy("dir", "new/dir"))
+	assert.ErrorIs(t, fsys.Copy("a.txt", "dir/b.txt"), fs.ErrExist)
+	data, err := fs.ReadFile(fsys, "new/dir/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(data))
+
+	// copy directory into itself
+	assert.ErrorIs(t, fsys.Copy("dir", "dir/sub"), fs.ErrInvalid)
+	assert.ErrorIs(t, fsys.Copy(".", "dir/sub"), fs.ErrInvalid)
+	_, err = fs.Stat(fsys, "dir/sub")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// rename
+	require.NoError(t, fsys.Rename("new/dir", "moved"))
+	_, err = fs.Stat(fsys, "new/dir")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fs.Stat(fsys, "moved/b.txt")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, fsys.Rename("a.txt", "moved"), fs.ErrExist)
+
+	// remove
+	require.NoError(t, fsys.Remove("moved"))
+	_, err = fs.Stat(fsys, "moved")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorIs(t, fsys.Remove("moved"), fs.ErrNotExist)
+
+	// invalid paths
+	assert.ErrorIs(t, fsys.Remove("../a.txt"), fs.ErrInvalid)
+	assert.ErrorIs(t, fsys.Remove("."), fs.ErrInvalid)
+
+	// the result is a valid fs.FS
+	assert.NoError(t, fstest.TestFS(fsys, "a.txt", "dir/b.txt", "new"))
+}
+
+func TestOSFS(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), 0o755))
+	testWriteFS(t, DirFS(dir))
+}
+
+func TestMemFS(t *testing.T) {
+	fsys := NewMemFS()
+	require.NoError(t, fsys.WriteFile("a.txt", []byte("a"), 0o644))
+	require.NoError(t, fsys.Mkdir("dir", 0o755))
+	testWriteFS(t, fsys)
+
+	// the parent directories, created by WriteFile, outlive the files
+	require.NoError(t, fsys.WriteFile("p/q/a.txt", []byte("a"), 0o644))
+	require.NoError(t, fsys.Rename("p/q/a.txt", "a2.txt"))
+	fi, err := fs.Stat(fsys, "p/q")
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+	require.NoError(t, fsys.WriteFile("p/b.txt", []byte("b"), 0o644))
+	require.NoError(t, fsys.Remove("p/b.txt"))
+	entries, err := fs.ReadDir(fsys, "p")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "only q is left")
+	assert.Error(t, fsys.WriteFile("a.txt/c.txt", nil, 0o644), "parent is a file")
+}